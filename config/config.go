@@ -0,0 +1,404 @@
+package config
+
+import (
+	"Learn_Jenkins/pkg/logging"
+	"Learn_Jenkins/pkg/password"
+	"Learn_Jenkins/pkg/sealer"
+	"Learn_Jenkins/pkg/token"
+	"Learn_Jenkins/pkg/tracing"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server   ServerConfig   `file:"server"`
+	Log      LogConfig      `file:"log"`
+	Tracing  TracingConfig  `file:"tracing"`
+	Storage  StorageConfig  `file:"storage"`
+	Database DatabaseConfig `file:"database"`
+	Password PasswordConfig `file:"password"`
+	Auth     AuthConfig     `file:"auth"`
+	Cursor   CursorConfig   `file:"cursor"`
+	Recovery RecoveryConfig `file:"recovery"`
+}
+
+type ServerConfig struct {
+	Port             int           `file:"port" env:"PORT" default:"8001"`
+	ShutdownTimeout  time.Duration `file:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	DrainDelay       time.Duration `file:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"5s"`
+	ReadinessTimeout time.Duration `file:"readiness_timeout" env:"READINESS_TIMEOUT" default:"2s"`
+}
+
+type LogConfig struct {
+	Format string `file:"format" env:"LOG_FORMAT" default:"json"`
+	Level  string `file:"level" env:"LOG_LEVEL" default:"info"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `file:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	File        string  `file:"file" env:"TRACING_FILE" default:"traces.jsonl"`
+	Endpoint    string  `file:"endpoint" env:"TRACING_OTLP_ENDPOINT" default:"http://localhost:4318"`
+	ServiceName string  `file:"service_name" env:"TRACING_SERVICE_NAME" default:"learn_jenkins"`
+	SampleRatio float64 `file:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
+type StorageConfig struct {
+	Backend string `file:"backend" env:"STORAGE" default:"database"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type DatabaseConfig struct {
+	Driver          string        `file:"driver" env:"DB_DRIVER" default:"postgres"`
+	Path            string        `file:"path" env:"DB_PATH" default:"learn_jenkins.db"`
+	Host            string        `file:"host" env:"DB_HOST"`
+	Port            int           `file:"port" env:"DB_PORT" default:"5432"`
+	Username        string        `file:"username" env:"DB_USERNAME"`
+	Password        string        `file:"password" env:"DB_PASSWORD"`
+	Name            string        `file:"name" env:"DB_NAME"`
+	TestName        string        `file:"test_name" env:"DB_NAME_TESTING"`
+	SSLMode         string        `file:"ssl_mode" env:"DB_SSL_MODE" default:"disable"`
+	MaxIdleConns    int           `file:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"10"`
+	MaxOpenConns    int           `file:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"100"`
+	ConnMaxLifetime time.Duration `file:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"300s"`
+	ConnMaxIdleTime time.Duration `file:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"60s"`
+	SlowQuery       time.Duration `file:"slow_query" env:"DB_SLOW_QUERY" default:"200ms"`
+}
+
+type PasswordConfig struct {
+	Algorithm         string `file:"algorithm" env:"PASSWORD_ALGORITHM" default:"argon2id"`
+	Argon2Memory      int    `file:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY" default:"65536"`
+	Argon2Iterations  int    `file:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS" default:"3"`
+	Argon2Parallelism int    `file:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM" default:"2"`
+	BcryptCost        int    `file:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" default:"12"`
+}
+
+func (c PasswordConfig) Params() password.Params {
+	return password.Params{
+		Algorithm:         c.Algorithm,
+		Argon2Memory:      uint32(c.Argon2Memory),
+		Argon2Iterations:  uint32(c.Argon2Iterations),
+		Argon2Parallelism: uint8(c.Argon2Parallelism),
+		BcryptCost:        c.BcryptCost,
+	}
+}
+
+type AuthConfig struct {
+	// JWTSecret signs access tokens; when empty a random key is used and
+	// every token becomes invalid on restart.
+	JWTSecret       string        `file:"jwt_secret" env:"AUTH_JWT_SECRET"`
+	Issuer          string        `file:"issuer" env:"AUTH_JWT_ISSUER" default:"learn_jenkins"`
+	AccessTokenTTL  time.Duration `file:"access_token_ttl" env:"AUTH_ACCESS_TOKEN_TTL" default:"15m"`
+	RefreshTokenTTL time.Duration `file:"refresh_token_ttl" env:"AUTH_REFRESH_TOKEN_TTL" default:"720h"`
+	// BootstrapUsername and BootstrapPassword create a first account at
+	// startup if it does not exist yet, since /users requires a login.
+	BootstrapUsername string `file:"bootstrap_username" env:"AUTH_BOOTSTRAP_USERNAME"`
+	BootstrapPassword string `file:"bootstrap_password" env:"AUTH_BOOTSTRAP_PASSWORD"`
+	// IdentityHeader carries the user ID set by an authenticating proxy. It
+	// is only trusted on requests coming straight from TrustedProxies, a
+	// comma-separated list of IP addresses or CIDR ranges; by default no
+	// proxy is trusted and callers must present a token.
+	IdentityHeader string `file:"identity_header" env:"AUTH_IDENTITY_HEADER" default:"X-User-ID"`
+	TrustedProxies string `file:"trusted_proxies" env:"AUTH_TRUSTED_PROXIES"`
+	// SignatureMaxSkew is how far the timestamp of a signed request may be
+	// from the server clock, and so how long its nonce is remembered.
+	// SignatureMaxBody caps the body read to check a signature, in bytes.
+	SignatureMaxSkew time.Duration `file:"signature_max_skew" env:"AUTH_SIGNATURE_MAX_SKEW" default:"5m"`
+	SignatureMaxBody int           `file:"signature_max_body" env:"AUTH_SIGNATURE_MAX_BODY" default:"1048576"`
+	// SigningEncryptionKey encrypts the stored signing client secrets: 32
+	// bytes written as hex. When empty a random key is used and every
+	// signing client stops working on restart.
+	SigningEncryptionKey string `file:"signing_encryption_key" env:"AUTH_SIGNING_ENCRYPTION_KEY"`
+}
+
+// SigningSealer returns the sealer for SigningEncryptionKey, or one with a
+// random key when it is empty.
+func (c AuthConfig) SigningSealer() (*sealer.Sealer, error) {
+	if c.SigningEncryptionKey == "" {
+		return sealer.NewRandom()
+	}
+	key, err := sealer.ParseKey(c.SigningEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return sealer.New(key)
+}
+
+// Proxies parses TrustedProxies; a bare address stands for itself alone.
+func (c AuthConfig) Proxies() ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, entry := range strings.Split(c.TrustedProxies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address or CIDR range %q", entry)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+type CursorConfig struct {
+	Secret string `file:"secret" env:"CURSOR_SECRET"`
+}
+
+type RecoveryConfig struct {
+	PanicReportFile string `file:"panic_report_file" env:"PANIC_REPORT_FILE"`
+}
+
+// Load builds the configuration from defaults, the optional config file
+// (YAML or TOML, picked by extension), the given .env files and finally the
+// process environment, each source overriding the previous one.
+func Load(configFile string, envFiles ...string) (*Config, error) {
+	cfg := &Config{}
+	var errs []error
+
+	walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, info reflect.StructField, path string) {
+		if value, ok := info.Tag.Lookup("default"); ok {
+			errs = append(errs, setField(field, path, value))
+		}
+	})
+
+	if configFile != "" {
+		values, err := readConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, info reflect.StructField, path string) {
+			if value, ok := lookupPath(values, path); ok {
+				errs = append(errs, setField(field, path, fmt.Sprint(value)))
+			}
+		})
+	}
+
+	env := map[string]string{}
+	for _, file := range envFiles {
+		values, err := godotenv.Read(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		for key, value := range values {
+			env[key] = value
+		}
+	}
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		env[key] = value
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "", func(field reflect.Value, info reflect.StructField, path string) {
+		key := info.Tag.Get("env")
+		if value, ok := env[key]; ok && key != "" && value != "" {
+			errs = append(errs, setField(field, key, value))
+		}
+	})
+
+	errs = append(errs, cfg.Validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port (PORT) must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay (SHUTDOWN_DRAIN_DELAY) must not be negative")
+	check(c.Server.ReadinessTimeout > 0, "server.readiness_timeout (READINESS_TIMEOUT) must be positive")
+
+	check(c.Log.Format == logging.FormatJSON || c.Log.Format == logging.FormatText,
+		"log.format (LOG_FORMAT) must be %q or %q, got %q", logging.FormatJSON, logging.FormatText, c.Log.Format)
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Log.Level)
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+	case tracing.ExporterFile:
+		check(c.Tracing.File != "", "tracing.file (TRACING_FILE) is required with the file exporter")
+	case tracing.ExporterOTLP:
+		check(c.Tracing.Endpoint != "", "tracing.endpoint (TRACING_OTLP_ENDPOINT) is required with the otlp exporter")
+	default:
+		check(false, "tracing.exporter (TRACING_EXPORTER) must be %q, %q, %q or %q, got %q",
+			tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterFile, tracing.ExporterOTLP, c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	switch c.Password.Algorithm {
+	case password.AlgorithmArgon2id:
+		check(c.Password.Argon2Parallelism >= 1 && c.Password.Argon2Parallelism <= 255,
+			"password.argon2_parallelism (PASSWORD_ARGON2_PARALLELISM) must be between 1 and 255, got %d", c.Password.Argon2Parallelism)
+		check(c.Password.Argon2Memory >= 8*c.Password.Argon2Parallelism && c.Password.Argon2Memory <= 4*1024*1024,
+			"password.argon2_memory (PASSWORD_ARGON2_MEMORY) must be between 8 KiB per thread and 4 GiB, got %d KiB", c.Password.Argon2Memory)
+		check(c.Password.Argon2Iterations >= 1, "password.argon2_iterations (PASSWORD_ARGON2_ITERATIONS) must be positive")
+	case password.AlgorithmBcrypt:
+		check(c.Password.BcryptCost >= bcrypt.MinCost && c.Password.BcryptCost <= bcrypt.MaxCost,
+			"password.bcrypt_cost (PASSWORD_BCRYPT_COST) must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, c.Password.BcryptCost)
+	default:
+		check(false, "password.algorithm (PASSWORD_ALGORITHM) must be %q or %q, got %q", password.AlgorithmArgon2id, password.AlgorithmBcrypt, c.Password.Algorithm)
+	}
+
+	check(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= token.MinSecretLength,
+		"auth.jwt_secret (AUTH_JWT_SECRET) must be at least %d bytes", token.MinSecretLength)
+	check(c.Auth.Issuer != "", "auth.issuer (AUTH_JWT_ISSUER) is required")
+	check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl (AUTH_ACCESS_TOKEN_TTL) must be positive")
+	check(c.Auth.RefreshTokenTTL > 0, "auth.refresh_token_ttl (AUTH_REFRESH_TOKEN_TTL) must be positive")
+	check((c.Auth.BootstrapUsername == "") == (c.Auth.BootstrapPassword == ""),
+		"auth.bootstrap_username (AUTH_BOOTSTRAP_USERNAME) and auth.bootstrap_password (AUTH_BOOTSTRAP_PASSWORD) must be set together")
+	check(c.Auth.IdentityHeader != "", "auth.identity_header (AUTH_IDENTITY_HEADER) is required")
+	check(c.Auth.SignatureMaxSkew > 0, "auth.signature_max_skew (AUTH_SIGNATURE_MAX_SKEW) must be positive")
+	check(c.Auth.SignatureMaxBody > 0, "auth.signature_max_body (AUTH_SIGNATURE_MAX_BODY) must be positive")
+	if c.Auth.SigningEncryptionKey != "" {
+		_, err = sealer.ParseKey(c.Auth.SigningEncryptionKey)
+		check(err == nil, "auth.signing_encryption_key (AUTH_SIGNING_ENCRYPTION_KEY): %v", err)
+	}
+	_, err = c.Auth.Proxies()
+	check(err == nil, "auth.trusted_proxies (AUTH_TRUSTED_PROXIES): %v", err)
+
+	switch c.Storage.Backend {
+	case StorageDatabase, StorageMemory:
+	default:
+		check(false, "storage.backend (STORAGE) must be %q or %q, got %q", StorageDatabase, StorageMemory, c.Storage.Backend)
+	}
+	if c.Storage.Backend != StorageDatabase {
+		return errors.Join(errs...)
+	}
+
+	switch c.Database.Driver {
+	case DriverPostgres:
+		check(c.Database.Host != "", "database.host (DB_HOST) is required")
+		check(c.Database.Port > 0 && c.Database.Port <= 65535, "database.port (DB_PORT) must be between 1 and 65535, got %d", c.Database.Port)
+		check(c.Database.Username != "", "database.username (DB_USERNAME) is required")
+		check(c.Database.Name != "", "database.name (DB_NAME) is required")
+	case DriverSQLite:
+		check(c.Database.Path != "", "database.path (DB_PATH) is required")
+	default:
+		check(false, "database.driver (DB_DRIVER) must be %q or %q, got %q", DriverPostgres, DriverSQLite, c.Database.Driver)
+	}
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns (DB_MAX_OPEN_CONNS) must be positive")
+	check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (DB_MAX_IDLE_CONNS) must be between 0 and database.max_open_conns")
+	check(c.Database.SlowQuery >= 0, "database.slow_query (DB_SLOW_QUERY) must not be negative")
+
+	return errors.Join(errs...)
+}
+
+func readConfigFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func walk(v reflect.Value, prefix string, visit func(reflect.Value, reflect.StructField, string)) {
+	for i := 0; i < v.NumField(); i++ {
+		info := v.Type().Field(i)
+		path := info.Tag.Get("file")
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if info.Type.Kind() == reflect.Struct && info.Type != reflect.TypeOf(time.Duration(0)) {
+			walk(v.Field(i), path, visit)
+			continue
+		}
+		visit(v.Field(i), info, path)
+	}
+}
+
+func lookupPath(values map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func setField(field reflect.Value, name, raw string) error {
+	switch {
+	case field.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", name, raw)
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", name, raw)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", name, raw)
+		}
+		field.SetFloat(f)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", name, raw)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.String:
+		field.SetString(raw)
+	default:
+		return fmt.Errorf("%s: unsupported field type %s", name, field.Type())
+	}
+	return nil
+}
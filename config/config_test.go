@@ -0,0 +1,334 @@
+package config
+
+import (
+	"Learn_Jenkins/pkg/password"
+	"Learn_Jenkins/pkg/sealer"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USERNAME", "postgres")
+	t.Setenv("DB_NAME", "learn_jenkins")
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8001, cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, DriverPostgres, cfg.Database.Driver)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, 100, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 300*time.Second, cfg.Database.ConnMaxLifetime)
+	assert.Equal(t, 200*time.Millisecond, cfg.Database.SlowQuery)
+	assert.Equal(t, "json", cfg.Log.Format)
+	assert.Equal(t, "info", cfg.Log.Level)
+	assert.Equal(t, "none", cfg.Tracing.Exporter)
+	assert.Equal(t, 1.0, cfg.Tracing.SampleRatio)
+	assert.Equal(t, password.DefaultParams(), cfg.Password.Params())
+	assert.Equal(t, 15*time.Minute, cfg.Auth.AccessTokenTTL)
+	assert.Equal(t, 30*24*time.Hour, cfg.Auth.RefreshTokenTTL)
+	assert.Empty(t, cfg.Auth.JWTSecret)
+	assert.Equal(t, "X-User-ID", cfg.Auth.IdentityHeader)
+	assert.Empty(t, cfg.Auth.TrustedProxies)
+	assert.Equal(t, 5*time.Minute, cfg.Auth.SignatureMaxSkew)
+	assert.Equal(t, 1<<20, cfg.Auth.SignatureMaxBody)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	configFile := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+  shutdown_timeout: 10s
+database:
+  host: file-host
+  port: 6543
+  username: file-user
+  name: file-db
+`)
+	envFile := writeFile(t, ".env", "DB_HOST=dotenv-host\nDB_NAME=dotenv-db\nPORT=\n")
+	t.Setenv("DB_NAME", "env-db")
+
+	cfg, err := Load(configFile, envFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, 10*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, 6543, cfg.Database.Port)
+	assert.Equal(t, "file-user", cfg.Database.Username)
+	assert.Equal(t, "dotenv-host", cfg.Database.Host)
+	assert.Equal(t, "env-db", cfg.Database.Name)
+}
+
+func TestLoad_TOMLFile(t *testing.T) {
+	configFile := writeFile(t, "config.toml", `
+[server]
+port = 9100
+drain_delay = "1s"
+
+[database]
+host = "toml-host"
+username = "toml-user"
+name = "toml-db"
+`)
+
+	cfg, err := Load(configFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9100, cfg.Server.Port)
+	assert.Equal(t, time.Second, cfg.Server.DrainDelay)
+	assert.Equal(t, "toml-host", cfg.Database.Host)
+}
+
+func TestLoad_MissingEnvFileIsIgnored(t *testing.T) {
+	setRequiredEnv(t)
+
+	_, err := Load("", filepath.Join(t.TempDir(), "missing.env"))
+
+	assert.NoError(t, err)
+}
+
+func TestLoad_ReportsAllProblems(t *testing.T) {
+	t.Setenv("PORT", "not-a-number")
+	t.Setenv("SHUTDOWN_TIMEOUT", "soon")
+	t.Setenv("DB_MAX_IDLE_CONNS", "500")
+
+	cfg, err := Load("")
+
+	assert.Nil(t, cfg)
+	assert.Error(t, err)
+	for _, problem := range []string{
+		`PORT: invalid integer "not-a-number"`,
+		`SHUTDOWN_TIMEOUT: invalid duration "soon"`,
+		"database.host (DB_HOST) is required",
+		"database.username (DB_USERNAME) is required",
+		"database.name (DB_NAME) is required",
+		"database.max_idle_conns (DB_MAX_IDLE_CONNS)",
+	} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestLoad_SQLiteDriver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_PATH", "/tmp/learn.db")
+
+	cfg, err := Load("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, DriverSQLite, cfg.Database.Driver)
+	assert.Equal(t, "/tmp/learn.db", cfg.Database.Path)
+}
+
+func TestLoad_MemoryStorageNeedsNoDatabase(t *testing.T) {
+	t.Setenv("STORAGE", "memory")
+	t.Setenv("DB_DRIVER", "mysql")
+
+	cfg, err := Load("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, StorageMemory, cfg.Storage.Backend)
+}
+
+func TestLoad_UnknownStorage(t *testing.T) {
+	t.Setenv("STORAGE", "redis")
+
+	_, err := Load("")
+
+	assert.ErrorContains(t, err, `storage.backend (STORAGE) must be "database" or "memory", got "redis"`)
+}
+
+func TestLoad_UnknownDriver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "mysql")
+
+	_, err := Load("")
+
+	assert.ErrorContains(t, err, `database.driver (DB_DRIVER) must be "postgres" or "sqlite", got "mysql"`)
+}
+
+func TestLoad_Logging(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("LOG_FORMAT", "text")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("DB_SLOW_QUERY", "1s")
+
+	cfg, err := Load("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "text", cfg.Log.Format)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, time.Second, cfg.Database.SlowQuery)
+}
+
+func TestLoad_InvalidLogging(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("LOG_FORMAT", "xml")
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("DB_SLOW_QUERY", "-1s")
+
+	_, err := Load("")
+
+	assert.ErrorContains(t, err, `log.format (LOG_FORMAT) must be "json" or "text", got "xml"`)
+	assert.ErrorContains(t, err, `log.level (LOG_LEVEL) must be debug, info, warn or error, got "verbose"`)
+	assert.ErrorContains(t, err, "database.slow_query (DB_SLOW_QUERY) must not be negative")
+}
+
+func TestLoad_Tracing(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("TRACING_EXPORTER", "file")
+	t.Setenv("TRACING_FILE", "/tmp/spans.jsonl")
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+
+	cfg, err := Load("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file", cfg.Tracing.Exporter)
+	assert.Equal(t, "/tmp/spans.jsonl", cfg.Tracing.File)
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+}
+
+func TestLoad_InvalidTracing(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("TRACING_EXPORTER", "zipkin")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+
+	_, err := Load("")
+
+	assert.ErrorContains(t, err, `tracing.exporter (TRACING_EXPORTER) must be "none", "stdout", "file" or "otlp", got "zipkin"`)
+	assert.ErrorContains(t, err, "tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1, got 2")
+
+	t.Setenv("TRACING_SAMPLE_RATIO", "half")
+	_, err = Load("")
+
+	assert.ErrorContains(t, err, `TRACING_SAMPLE_RATIO: invalid number "half"`)
+}
+
+func TestLoad_Password(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("PASSWORD_ALGORITHM", "bcrypt")
+	t.Setenv("PASSWORD_BCRYPT_COST", "10")
+
+	cfg, err := Load("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, password.AlgorithmBcrypt, cfg.Password.Params().Algorithm)
+	assert.Equal(t, 10, cfg.Password.Params().BcryptCost)
+}
+
+func TestLoad_InvalidPassword(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("PASSWORD_ARGON2_PARALLELISM", "300")
+	t.Setenv("PASSWORD_ARGON2_ITERATIONS", "0")
+
+	_, err := Load("")
+
+	assert.ErrorContains(t, err, "password.argon2_parallelism (PASSWORD_ARGON2_PARALLELISM) must be between 1 and 255, got 300")
+	assert.ErrorContains(t, err, "password.argon2_iterations (PASSWORD_ARGON2_ITERATIONS) must be positive")
+
+	t.Setenv("PASSWORD_ALGORITHM", "bcrypt")
+	t.Setenv("PASSWORD_BCRYPT_COST", "40")
+	_, err = Load("")
+
+	assert.ErrorContains(t, err, "password.bcrypt_cost (PASSWORD_BCRYPT_COST) must be between 4 and 31, got 40")
+
+	t.Setenv("PASSWORD_ALGORITHM", "md5")
+	_, err = Load("")
+
+	assert.ErrorContains(t, err, `password.algorithm (PASSWORD_ALGORITHM) must be "argon2id" or "bcrypt", got "md5"`)
+}
+
+func TestLoad_InvalidAuth(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("AUTH_JWT_SECRET", "too-short")
+	t.Setenv("AUTH_ACCESS_TOKEN_TTL", "-1m")
+	t.Setenv("AUTH_BOOTSTRAP_USERNAME", "admin")
+	t.Setenv("AUTH_TRUSTED_PROXIES", "10.0.0.0/8, proxy.internal")
+	t.Setenv("AUTH_SIGNATURE_MAX_SKEW", "0s")
+	t.Setenv("AUTH_SIGNING_ENCRYPTION_KEY", "00ff")
+
+	_, err := Load("")
+
+	assert.ErrorContains(t, err, "auth.jwt_secret (AUTH_JWT_SECRET) must be at least 32 bytes")
+	assert.ErrorContains(t, err, "auth.access_token_ttl (AUTH_ACCESS_TOKEN_TTL) must be positive")
+	assert.ErrorContains(t, err, "must be set together")
+	assert.ErrorContains(t, err, `auth.trusted_proxies (AUTH_TRUSTED_PROXIES): invalid address or CIDR range "proxy.internal"`)
+	assert.ErrorContains(t, err, "auth.signature_max_skew (AUTH_SIGNATURE_MAX_SKEW) must be positive")
+	assert.ErrorContains(t, err, "auth.signing_encryption_key (AUTH_SIGNING_ENCRYPTION_KEY): must be 32 bytes, got 2")
+}
+
+func TestAuthConfig_SigningSealer(t *testing.T) {
+	key := strings.Repeat("0f", 32)
+	sealed, err := mustSealer(t, AuthConfig{SigningEncryptionKey: key}).Seal("secret", "sc_1")
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+
+	opened, err := mustSealer(t, AuthConfig{SigningEncryptionKey: key}).Open(sealed, "sc_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", opened)
+	_, err = mustSealer(t, AuthConfig{}).Open(sealed, "sc_1")
+	assert.Error(t, err, "an empty key falls back to a random one")
+}
+
+func mustSealer(t *testing.T, cfg AuthConfig) *sealer.Sealer {
+	s, err := cfg.SigningSealer()
+	if err != nil {
+		t.Fatalf("failed to build the sealer: %v", err)
+	}
+	return s
+}
+
+func TestAuthConfig_Proxies(t *testing.T) {
+	proxies, err := AuthConfig{TrustedProxies: "10.1.2.3/8, 192.168.0.7,::1"}.Proxies()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.0.7/32"),
+		netip.MustParsePrefix("::1/128"),
+	}, proxies)
+}
+
+func TestLoad_UnsupportedConfigFile(t *testing.T) {
+	configFile := writeFile(t, "config.json", `{}`)
+
+	_, err := Load(configFile)
+
+	assert.ErrorContains(t, err, "unsupported config file format")
+}
+
+func TestDatabaseConfig_DSN(t *testing.T) {
+	cfg := DatabaseConfig{Host: "db", Port: 5432, Username: "app", Password: "p@ss word", SSLMode: "disable"}
+
+	assert.Equal(t, "postgresql://app:p%40ss%20word@db:5432/learn?sslmode=disable", cfg.DSN("learn"))
+}
+
+func TestInitDatabase_SQLite(t *testing.T) {
+	db, err := InitDatabase(DatabaseConfig{Driver: DriverSQLite, Path: filepath.Join(t.TempDir(), "learn.db")})
+	assert.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	defer sqlDB.Close()
+	assert.NoError(t, sqlDB.Ping())
+	assert.Equal(t, 1, sqlDB.Stats().MaxOpenConnections)
+}
@@ -1,56 +1,110 @@
 package config
 
 import (
+	"Learn_Jenkins/pkg/logging"
+	"Learn_Jenkins/pkg/metrics"
 	"fmt"
+	"log/slog"
 	"net/url"
-	"time"
 
-	"os"
-	"strconv"
-
-	"github.com/joho/godotenv"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func InitDatabase() (*gorm.DB, error) {
-	err := godotenv.Load()
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=case_sensitive_like(1)"
+
+func (c DatabaseConfig) DSN(name string) string {
+	uri := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(c.Username, c.Password),
+		Host:     fmt.Sprintf("%s:%d", c.Host, c.Port),
+		Path:     "/" + name,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	return uri.String()
+}
+
+// SQLiteDSN opens path as a file database, or a private in-memory database
+// when path is ":memory:". LIKE is made case-sensitive to match Postgres.
+func SQLiteDSN(path string) string {
+	if path == ":memory:" {
+		return "file::memory:?" + sqlitePragmas
+	}
+	return "file:" + path + "?" + sqlitePragmas
+}
+
+func (c DatabaseConfig) dialector(name, path string) (gorm.Dialector, error) {
+	switch c.Driver {
+	case DriverPostgres, "":
+		return postgres.Open(c.DSN(name)), nil
+	case DriverSQLite:
+		return sqlite.Open(SQLiteDSN(path)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", c.Driver)
+	}
+}
+
+func InitDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
+	dialector, err := cfg.dialector(cfg.Name, cfg.Path)
 	if err != nil {
 		return nil, err
 	}
-
-	username := os.Getenv("DB_USERNAME")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	portStr := os.Getenv("DB_PORT")
-	port, err := strconv.Atoi(portStr)
+	db, err := openDatabase(cfg, dialector)
 	if err != nil {
-		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
+		return nil, err
 	}
-	name := os.Getenv("DB_NAME")
 
-	encodedPassword := url.QueryEscape(password)
-	uri := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=disable",
-		username,
-		encodedPassword,
-		host,
-		port,
-		name,
-	)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	name := cfg.Name
+	if cfg.Driver == DriverSQLite {
+		name = cfg.Path
+	}
+	if err := metrics.RegisterDBStats(sqlDB, name); err != nil {
+		return nil, fmt.Errorf("register database metrics: %w", err)
+	}
+	return db, nil
+}
 
-	db, err := gorm.Open(postgres.Open(uri), &gorm.Config{})
+func openDatabase(cfg DatabaseConfig, dialector gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		TranslateError: true,
+		Logger:         logging.NewGormLogger(slog.Default(), cfg.SlowQuery),
+	})
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(queryCommentPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(queryMetricsPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(queryTracingPlugin{}); err != nil {
+		return nil, err
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Duration(300) * time.Second)
-	sqlDB.SetConnMaxIdleTime(time.Duration(60) * time.Second)
+	if cfg.Driver == DriverSQLite {
+		// SQLite allows a single writer, and every connection to ":memory:"
+		// would see its own empty database.
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+		return db, nil
+	}
+
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 	return db, nil
 }
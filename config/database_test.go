@@ -0,0 +1,40 @@
+package config
+
+import (
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/logging"
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenDatabase_LogsRedactPasswordHash(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, "debug")
+	require.NoError(t, err)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	db, err := InitTestDatabase(DatabaseConfig{Driver: DriverSQLite})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+	require.NoError(t, db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL UNIQUE, password_hash TEXT NOT NULL)").Error)
+
+	user := model.User{Username: "arthur", PasswordHash: "$argon2id$created"}
+	require.NoError(t, db.Create(&user).Error)
+	require.NoError(t, db.Model(&user).Updates(map[string]interface{}{"password_hash": model.PasswordHash("$argon2id$updated")}).Error)
+
+	var stored model.User
+	require.NoError(t, db.First(&stored, user.ID).Error)
+	assert.Equal(t, "$argon2id$updated", string(stored.PasswordHash))
+
+	logs := buf.String()
+	assert.Contains(t, logs, "arthur")
+	assert.Contains(t, logs, "[REDACTED]")
+	assert.NotContains(t, logs, "$argon2id$")
+}
@@ -3,39 +3,25 @@ package config
 import (
 	"database/sql"
 	"fmt"
-	"net/url"
-	"os"
-	"strconv"
+	"log/slog"
 	"strings"
 
 	"errors"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func CreateTestDatabase() error {
-	err := godotenv.Load("../.env")
-	if err != nil {
-		return err
+func CreateTestDatabase(cfg DatabaseConfig) error {
+	if cfg.Driver == DriverSQLite {
+		return nil
 	}
 
-	username := os.Getenv("DB_USERNAME")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	portStr := os.Getenv("DB_PORT")
-	name := os.Getenv("DB_NAME_TESTING")
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return fmt.Errorf("invalid DB_PORT: %w", err)
+	if cfg.TestName == "" {
+		return errors.New("database.test_name (DB_NAME_TESTING) is required")
 	}
 
-	encodedPassword := url.QueryEscape(password)
-	uri := fmt.Sprintf("postgresql://%s:%s@%s:%d/postgres?sslmode=disable",
-		username, encodedPassword, host, port)
-
-	db, err := gorm.Open(postgres.Open(uri), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(cfg.DSN("postgres")), &gorm.Config{})
 	if err != nil {
 		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
@@ -47,9 +33,9 @@ func CreateTestDatabase() error {
 	defer sqlDB.Close()
 
 	var tmp int
-	err = sqlDB.QueryRow("SELECT 1 FROM pg_database WHERE datname = $1", name).Scan(&tmp)
+	err = sqlDB.QueryRow("SELECT 1 FROM pg_database WHERE datname = $1", cfg.TestName).Scan(&tmp)
 	if err == nil {
-		fmt.Printf("database %s already exists\n", name)
+		slog.Info("test database already exists", "name", cfg.TestName)
 		return nil
 	}
 
@@ -57,38 +43,23 @@ func CreateTestDatabase() error {
 		return fmt.Errorf("failed to check if database exists: %w", err)
 	}
 
-	safeName := strings.ReplaceAll(name, `"`, `""`)
+	safeName := strings.ReplaceAll(cfg.TestName, `"`, `""`)
 
 	_, err = sqlDB.Exec(fmt.Sprintf(`CREATE DATABASE "%s"`, safeName))
 	if err != nil {
 		return fmt.Errorf("failed to create database: %w", err)
 	}
 
-	fmt.Println("Database Test created successfully")
+	slog.Info("test database created", "name", cfg.TestName)
 	return nil
 }
 
-func DropTestDatabase() error {
-	err := godotenv.Load("../.env")
-	if err != nil {
-		return fmt.Errorf("failed to load .env: %w", err)
-	}
-
-	username := os.Getenv("DB_USERNAME")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	portStr := os.Getenv("DB_PORT")
-	name := os.Getenv("DB_NAME_TESTING")
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return fmt.Errorf("invalid DB_PORT: %w", err)
+func DropTestDatabase(cfg DatabaseConfig) error {
+	if cfg.Driver == DriverSQLite {
+		return nil
 	}
 
-	encodedPassword := url.QueryEscape(password)
-	uri := fmt.Sprintf("postgresql://%s:%s@%s:%d/postgres?sslmode=disable",
-		username, encodedPassword, host, port)
-
-	db, err := gorm.Open(postgres.Open(uri), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(cfg.DSN("postgres")), &gorm.Config{})
 	if err != nil {
 		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
@@ -99,38 +70,26 @@ func DropTestDatabase() error {
 	}
 	defer sqlDB.Close()
 
-	safeName := strings.ReplaceAll(name, `"`, `""`)
+	safeName := strings.ReplaceAll(cfg.TestName, `"`, `""`)
 
 	_, err = sqlDB.Exec(fmt.Sprintf(`DROP DATABASE IF EXISTS "%s"`, safeName))
 	if err != nil {
 		return fmt.Errorf("failed to drop database: %w", err)
 	}
 
-	fmt.Println("Database Test dropped successfully")
+	slog.Info("test database dropped", "name", cfg.TestName)
 	return nil
 }
 
-func InitTestDatabase() (*gorm.DB, error) {
-	err := godotenv.Load("../.env")
+// InitTestDatabase connects to DB_NAME_TESTING on Postgres; with the SQLite
+// driver every call returns a fresh in-memory database.
+func InitTestDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
+	dialector, err := cfg.dialector(cfg.TestName, ":memory:")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load .env: %w", err)
+		return nil, err
 	}
 
-	username := os.Getenv("DB_USERNAME")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	portStr := os.Getenv("DB_PORT")
-	name := os.Getenv("DB_NAME_TESTING")
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
-	}
-
-	encodedPassword := url.QueryEscape(password)
-	uri := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s?sslmode=disable",
-		username, encodedPassword, host, port, name)
-
-	db, err := gorm.Open(postgres.Open(uri), &gorm.Config{})
+	db, err := openDatabase(cfg, dialector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to test database: %w", err)
 	}
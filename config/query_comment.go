@@ -0,0 +1,53 @@
+package config
+
+import (
+	"Learn_Jenkins/pkg/requestid"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// queryCommentPlugin tags every statement with /* request_id=... */ so a slow
+// or failing query in the database logs can be traced back to the HTTP request
+// that issued it. Inserts carry the comment before VALUES because the SQLite
+// dialect builds the INSERT clause itself and drops anything attached to it.
+type queryCommentPlugin struct{}
+
+func (queryCommentPlugin) Name() string {
+	return "request_id_comment"
+}
+
+func (queryCommentPlugin) Initialize(db *gorm.DB) error {
+	callbacks := []struct {
+		register func(name string, fn func(*gorm.DB)) error
+		clause   string
+	}{
+		{db.Callback().Create().Before("gorm:create").Register, "VALUES"},
+		{db.Callback().Query().Before("gorm:query").Register, "SELECT"},
+		{db.Callback().Update().Before("gorm:update").Register, "UPDATE"},
+		{db.Callback().Delete().Before("gorm:delete").Register, "DELETE"},
+	}
+	for _, callback := range callbacks {
+		if err := callback.register("request_id:comment", addQueryComment(callback.clause)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addQueryComment(clauseName string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		id := requestid.FromContext(db.Statement.Context)
+		if id == "" {
+			return
+		}
+		// IDs are validated by the middleware; stripping the comment
+		// terminator keeps a bad caller from breaking out of the comment.
+		id = strings.ReplaceAll(id, "*/", "")
+
+		c := db.Statement.Clauses[clauseName]
+		c.BeforeExpression = clause.Expr{SQL: "/* " + requestid.LogKey + "=" + id + " */"}
+		db.Statement.Clauses[clauseName] = c
+	}
+}
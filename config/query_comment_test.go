@@ -0,0 +1,53 @@
+package config
+
+import (
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/requestid"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestQueryComment(t *testing.T) {
+	db, err := InitTestDatabase(DatabaseConfig{Driver: DriverSQLite})
+	assert.NoError(t, err)
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+	assert.NoError(t, db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL UNIQUE, password_hash TEXT NOT NULL DEFAULT '')").Error)
+
+	var statements []string
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	assert.NoError(t, db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	assert.NoError(t, db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+	assert.NoError(t, db.Callback().Delete().After("gorm:delete").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	ctx := requestid.NewContext(context.Background(), "req-42")
+	user := model.User{Username: "arthur"}
+	var count int64
+	assert.NoError(t, db.WithContext(ctx).Create(&user).Error)
+	assert.NoError(t, db.WithContext(ctx).Model(&model.User{}).Count(&count).Error)
+	assert.NoError(t, db.WithContext(ctx).First(&user, user.ID).Error)
+	assert.NoError(t, db.WithContext(ctx).Model(&user).Update("username", "renamed").Error)
+	assert.NoError(t, db.WithContext(ctx).Delete(&model.User{}, user.ID).Error)
+	assert.Equal(t, int64(1), count)
+
+	assert.Len(t, statements, 5)
+	for _, statement := range statements {
+		assert.Contains(t, statement, "/* request_id=req-42 */")
+	}
+
+	statements = nil
+	assert.NoError(t, db.WithContext(context.Background()).Find(&[]model.User{}).Error)
+	assert.Len(t, statements, 1)
+	assert.NotContains(t, statements[0], "request_id")
+}
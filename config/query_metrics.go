@@ -0,0 +1,60 @@
+package config
+
+import (
+	"Learn_Jenkins/pkg/metrics"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// queryMetricsPlugin observes how long every GORM statement takes, labeled by
+// the callback chain (create, query, update, delete, row or raw) that ran it.
+type queryMetricsPlugin struct{}
+
+func (queryMetricsPlugin) Name() string {
+	return "query_metrics"
+}
+
+func (queryMetricsPlugin) Initialize(db *gorm.DB) error {
+	callbacks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", db.Callback().Create().Before("gorm:create").Register, db.Callback().Create().After("gorm:create").Register},
+		{"query", db.Callback().Query().Before("gorm:query").Register, db.Callback().Query().After("gorm:query").Register},
+		{"update", db.Callback().Update().Before("gorm:update").Register, db.Callback().Update().After("gorm:update").Register},
+		{"delete", db.Callback().Delete().Before("gorm:delete").Register, db.Callback().Delete().After("gorm:delete").Register},
+		{"row", db.Callback().Row().Before("gorm:row").Register, db.Callback().Row().After("gorm:row").Register},
+		{"raw", db.Callback().Raw().Before("gorm:raw").Register, db.Callback().Raw().After("gorm:raw").Register},
+	}
+	for _, callback := range callbacks {
+		if err := callback.before("metrics:before_"+callback.operation, startQueryTimer); err != nil {
+			return err
+		}
+		if err := callback.after("metrics:after_"+callback.operation, observeQuery(callback.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQueryTimer(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		metrics.QueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/metrics"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	clientmodel "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func querySamples(t *testing.T, operation string) uint64 {
+	var m clientmodel.Metric
+	if err := metrics.QueryDuration.WithLabelValues(operation).(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("failed to read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestQueryMetrics(t *testing.T) {
+	db, err := InitTestDatabase(DatabaseConfig{Driver: DriverSQLite})
+	assert.NoError(t, err)
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	operations := []string{"create", "query", "update", "delete", "row", "raw"}
+	before := map[string]uint64{}
+	for _, operation := range operations {
+		before[operation] = querySamples(t, operation)
+	}
+
+	assert.NoError(t, db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL UNIQUE, password_hash TEXT NOT NULL DEFAULT '')").Error)
+	user := model.User{Username: "arthur"}
+	var username string
+	assert.NoError(t, db.Create(&user).Error)
+	assert.NoError(t, db.First(&user, user.ID).Error)
+	assert.NoError(t, db.Model(&user).Update("username", "renamed").Error)
+	assert.NoError(t, db.Raw("SELECT username FROM users").Row().Scan(&username))
+	assert.NoError(t, db.Delete(&model.User{}, user.ID).Error)
+	assert.Equal(t, "renamed", username)
+
+	for _, operation := range operations {
+		assert.Equal(t, before[operation]+1, querySamples(t, operation), operation)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"Learn_Jenkins/domain/model"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryTracing(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+
+	db, err := InitTestDatabase(DatabaseConfig{Driver: DriverSQLite})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+	require.NoError(t, db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL UNIQUE, password_hash TEXT NOT NULL DEFAULT '')").Error)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	user := model.User{Username: "arthur"}
+	assert.NoError(t, db.WithContext(ctx).Create(&user).Error)
+	assert.Error(t, db.WithContext(ctx).Create(&model.User{Username: "arthur"}).Error)
+	assert.Error(t, db.WithContext(ctx).First(&model.User{}, 99).Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	// CREATE TABLE, the three statements under test, then the parent.
+	require.Len(t, spans, 5)
+	created, duplicate, notFound := spans[1], spans[2], spans[3]
+
+	for _, span := range []sdktrace.ReadOnlySpan{created, duplicate, notFound} {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(), attribute.String("db.system.name", "sqlite"))
+		assert.Contains(t, span.Attributes(), attribute.String("db.collection.name", "users"))
+	}
+	assert.Equal(t, "gorm.create", created.Name())
+	assert.Contains(t, created.Attributes(), attribute.Int64("db.response.rows_affected", 1))
+	assert.Equal(t, codes.Unset, created.Status().Code)
+	assert.Equal(t, codes.Error, duplicate.Status().Code)
+	assert.Equal(t, "gorm.query", notFound.Name())
+	assert.Equal(t, codes.Unset, notFound.Status().Code, "record not found is not a failure")
+}
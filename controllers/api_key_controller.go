@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController interface {
+	CreateAPIKey(*gin.Context)
+	FindAPIKeys(*gin.Context)
+	RevokeAPIKey(*gin.Context)
+}
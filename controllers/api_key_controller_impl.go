@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"Learn_Jenkins/pkg/validation"
+	"Learn_Jenkins/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyControllerImpl struct {
+	apiKeyService services.APIKeyService
+	validator     *validation.Validator
+}
+
+func NewAPIKeyController(apiKeyService services.APIKeyService, validator *validation.Validator) APIKeyController {
+	return &apiKeyControllerImpl{apiKeyService: apiKeyService, validator: validator}
+}
+
+func (s *apiKeyControllerImpl) CreateAPIKey(ctx *gin.Context) {
+	createCredential(ctx, s.validator, s.apiKeyService.CreateAPIKey)
+}
+
+func (s *apiKeyControllerImpl) FindAPIKeys(ctx *gin.Context) {
+	findCredentials(ctx, s.apiKeyService.FindAPIKeys)
+}
+
+func (s *apiKeyControllerImpl) RevokeAPIKey(ctx *gin.Context) {
+	revokeCredential(ctx, "key_id", s.apiKeyService.RevokeAPIKey)
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/problem"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPIKeyService struct {
+	created *dto.APIKeyCreatedResponse
+	keys    *dto.APIKeyListResponse
+	err     error
+	userID  uint
+	keyID   uint
+	request *dto.APIKeyRequest
+}
+
+func (f *fakeAPIKeyService) CreateAPIKey(ctx context.Context, userID uint, req *dto.APIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	f.userID, f.request = userID, req
+	return f.created, f.err
+}
+
+func (f *fakeAPIKeyService) FindAPIKeys(ctx context.Context, userID uint) (*dto.APIKeyListResponse, error) {
+	f.userID = userID
+	return f.keys, f.err
+}
+
+func (f *fakeAPIKeyService) RevokeAPIKey(ctx context.Context, userID, id uint) error {
+	f.userID, f.keyID = userID, id
+	return f.err
+}
+
+func (f *fakeAPIKeyService) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	return nil, f.err
+}
+
+func TestAPIKeyController_CreateAPIKey(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := &fakeAPIKeyService{created: &dto.APIKeyCreatedResponse{
+		APIKeyResponse: dto.APIKeyResponse{ID: 3, Name: "batch", Prefix: "0123456789abcdef", Scopes: []string{"users:read_all"}, CreatedAt: createdAt},
+		Key:            "lj_0123456789abcdef_secret",
+	}}
+
+	w := serveCredentials(NewAPIKeyController(fake, testValidator).CreateAPIKey, `{"name":"batch","scopes":["users:read_all"]}`, ownerParam)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"id":3,"name":"batch","prefix":"0123456789abcdef","scopes":["users:read_all"],"expires_at":null,"last_used_at":null,"revoked_at":null,"created_at":"2025-01-01T12:00:00Z","key":"lj_0123456789abcdef_secret"}`, w.Body.String())
+	assert.Equal(t, uint(7), fake.userID)
+	assert.Equal(t, &dto.APIKeyRequest{Name: "batch", Scopes: []string{"users:read_all"}}, fake.request)
+}
+
+func TestAPIKeyController_CreateAPIKey_ValidationError(t *testing.T) {
+	fake := &fakeAPIKeyService{}
+
+	w := serveCredentials(NewAPIKeyController(fake, testValidator).CreateAPIKey, `{"scopes":["users:*"],"expires_at":"2000-01-01T00:00:00Z"}`, ownerParam)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var body problem.Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	fields := map[string]string{}
+	for _, field := range body.Errors {
+		fields[field.Field] = field.Code
+	}
+	assert.Equal(t, map[string]string{"name": "required", "scopes[0]": "permission", "expires_at": "gt"}, fields)
+	assert.Nil(t, fake.request)
+}
+
+func TestAPIKeyController_FindAndRevoke(t *testing.T) {
+	fake := &fakeAPIKeyService{keys: &dto.APIKeyListResponse{}}
+	ctrl := NewAPIKeyController(fake, testValidator)
+
+	w := serveCredentials(ctrl.FindAPIKeys, "", ownerParam)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(7), fake.userID)
+
+	w = serveCredentials(ctrl.RevokeAPIKey, "", gin.Param{Key: "id", Value: "8"}, gin.Param{Key: "key_id", Value: "3"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, uint(8), fake.userID)
+	assert.Equal(t, uint(3), fake.keyID)
+}
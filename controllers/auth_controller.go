@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController interface {
+	Login(*gin.Context)
+	Refresh(*gin.Context)
+	Logout(*gin.Context)
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/validation"
+	"Learn_Jenkins/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type authControllerImpl struct {
+	authService services.AuthService
+	validator   *validation.Validator
+}
+
+func NewAuthController(authService services.AuthService, validator *validation.Validator) AuthController {
+	return &authControllerImpl{authService: authService, validator: validator}
+}
+
+func (s *authControllerImpl) Login(ctx *gin.Context) {
+	request := &dto.LoginRequest{}
+	if !bindJSON(ctx, s.validator, request) {
+		return
+	}
+
+	tokens, err := s.authService.Login(ctx.Request.Context(), request)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	writeTokens(ctx, tokens)
+}
+
+func (s *authControllerImpl) Refresh(ctx *gin.Context) {
+	request := &dto.RefreshRequest{}
+	if !bindJSON(ctx, s.validator, request) {
+		return
+	}
+
+	tokens, err := s.authService.Refresh(ctx.Request.Context(), request)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	writeTokens(ctx, tokens)
+}
+
+func (s *authControllerImpl) Logout(ctx *gin.Context) {
+	request := &dto.RefreshRequest{}
+	if !bindJSON(ctx, s.validator, request) {
+		return
+	}
+
+	err := s.authService.Logout(ctx.Request.Context(), request)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// writeTokens forbids caching, as RFC 6749 requires for token responses.
+func writeTokens(ctx *gin.Context, tokens *dto.TokenResponse) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, tokens)
+}
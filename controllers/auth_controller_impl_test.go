@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/services"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAuthService struct {
+	tokens    *dto.TokenResponse
+	err       error
+	loginReq  *dto.LoginRequest
+	logoutReq *dto.RefreshRequest
+}
+
+func (f *fakeAuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	f.loginReq = req
+	return f.tokens, f.err
+}
+
+func (f *fakeAuthService) Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.TokenResponse, error) {
+	return f.tokens, f.err
+}
+
+func (f *fakeAuthService) Logout(ctx context.Context, req *dto.RefreshRequest) error {
+	f.logoutReq = req
+	return f.err
+}
+
+func (f *fakeAuthService) VerifyAccessToken(ctx context.Context, token string) (*auth.Principal, error) {
+	return nil, f.err
+}
+
+func serveAuth(handler func(*gin.Context), body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	handler(c)
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestAuthController_Login_Success(t *testing.T) {
+	fake := &fakeAuthService{tokens: &dto.TokenResponse{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}}
+	ctrl := NewAuthController(services.AuthService(fake), testValidator)
+
+	w := serveAuth(ctrl.Login, `{"username":"arthur","password":"correct-Horse-battery"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"access_token":"access","token_type":"Bearer","expires_in":900,"refresh_token":"refresh"}`, w.Body.String())
+	assert.Equal(t, &dto.LoginRequest{Username: "arthur", Password: "correct-Horse-battery"}, fake.loginReq)
+}
+
+func TestAuthController_Login_ValidationError(t *testing.T) {
+	fake := &fakeAuthService{}
+	ctrl := NewAuthController(services.AuthService(fake), testValidator)
+
+	w := serveAuth(ctrl.Login, `{"username":"arthur"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp problem.Details
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "password", resp.Errors[0].Field)
+	assert.Nil(t, fake.loginReq)
+}
+
+func TestAuthController_Login_InvalidCredentials(t *testing.T) {
+	ctrl := NewAuthController(&fakeAuthService{err: services.ErrInvalidCredentials}, testValidator)
+
+	w := serveAuth(ctrl.Login, `{"username":"arthur","password":"wrong"}`)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var resp problem.Details
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "invalid_credentials", resp.Code)
+}
+
+func TestAuthController_Refresh(t *testing.T) {
+	ctrl := NewAuthController(&fakeAuthService{tokens: &dto.TokenResponse{AccessToken: "access"}}, testValidator)
+
+	w := serveAuth(ctrl.Refresh, `{"refresh_token":"refresh"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	ctrl = NewAuthController(&fakeAuthService{err: services.ErrInvalidRefreshToken}, testValidator)
+
+	w = serveAuth(ctrl.Refresh, `{"refresh_token":"reused"}`)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var resp problem.Details
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "invalid_refresh_token", resp.Code)
+}
+
+func TestAuthController_Logout(t *testing.T) {
+	fake := &fakeAuthService{}
+	ctrl := NewAuthController(services.AuthService(fake), testValidator)
+
+	w := serveAuth(ctrl.Logout, `{"refresh_token":"refresh"}`)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, &dto.RefreshRequest{RefreshToken: "refresh"}, fake.logoutReq)
+
+	w = serveAuth(ctrl.Logout, `not json`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
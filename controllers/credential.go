@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/validation"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The API key and signing client controllers serve the same three endpoints
+// under /users/:id for the machine credentials a user owns; these handlers
+// hold what they share.
+
+// createCredential responds with the new credential, which carries its
+// secret for the only time, so the response must not be cached.
+func createCredential[Req, Resp any](ctx *gin.Context, validator *validation.Validator, create func(context.Context, uint, *Req) (Resp, error)) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+	request := new(Req)
+	if !bindJSON(ctx, validator, request) {
+		return
+	}
+
+	credential, err := create(ctx.Request.Context(), id, request)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusCreated, credential)
+}
+
+func findCredentials[T any](ctx *gin.Context, find func(context.Context, uint) (*dto.ListResponse[T], error)) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	credentials, err := find(ctx.Request.Context(), id)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	writeCollection(ctx, credentials)
+}
+
+// revokeCredential reads the ID of the credential from param.
+func revokeCredential(ctx *gin.Context, param string, revoke func(context.Context, uint, uint) error) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+	credentialID, ok := parseIDParam(ctx, param)
+	if !ok {
+		return
+	}
+
+	if err := revoke(ctx.Request.Context(), id, credentialID); err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
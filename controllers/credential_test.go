@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/repositories"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveCredentials(handler func(*gin.Context), body string, params ...gin.Param) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = params
+	handler(c)
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+var ownerParam = gin.Param{Key: "id", Value: "7"}
+
+func TestCreateCredential(t *testing.T) {
+	var (
+		userID  uint
+		request *dto.SigningClientRequest
+		err     error
+	)
+	handler := func(ctx *gin.Context) {
+		createCredential(ctx, testValidator, func(_ context.Context, id uint, req *dto.SigningClientRequest) (*dto.SigningClientCreatedResponse, error) {
+			userID, request = id, req
+			return &dto.SigningClientCreatedResponse{Secret: "secret"}, err
+		})
+	}
+
+	w := serveCredentials(handler, `{"name":"billing"}`, ownerParam)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), `"secret":"secret"`)
+	assert.Equal(t, uint(7), userID)
+	assert.Equal(t, &dto.SigningClientRequest{Name: "billing"}, request)
+
+	request = nil
+	w = serveCredentials(handler, `{}`, ownerParam)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	w = serveCredentials(handler, `{"name":"billing"}`, gin.Param{Key: "id", Value: "seven"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, request, "invalid requests never reach the service")
+
+	err = repositories.ErrUserNotFound
+	w = serveCredentials(handler, `{"name":"billing"}`, ownerParam)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestFindCredentials(t *testing.T) {
+	var userID uint
+	list := &dto.APIKeyListResponse{}
+	var err error
+	handler := func(ctx *gin.Context) {
+		findCredentials(ctx, func(_ context.Context, id uint) (*dto.APIKeyListResponse, error) {
+			userID = id
+			return list, err
+		})
+	}
+
+	w := serveCredentials(handler, "", ownerParam)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(7), userID)
+	assert.JSONEq(t, `{"items":[],"total":0,"limit":0,"offset":0,"has_more":false,"links":{}}`, w.Body.String())
+
+	err = repositories.ErrUserNotFound
+	w = serveCredentials(handler, "", ownerParam)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeCredential(t *testing.T) {
+	var userID, credentialID uint
+	var err error
+	handler := func(ctx *gin.Context) {
+		revokeCredential(ctx, "credential_id", func(_ context.Context, id, credential uint) error {
+			userID, credentialID = id, credential
+			return err
+		})
+	}
+
+	w := serveCredentials(handler, "", ownerParam, gin.Param{Key: "credential_id", Value: "3"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, uint(7), userID)
+	assert.Equal(t, uint(3), credentialID)
+
+	w = serveCredentials(handler, "", ownerParam, gin.Param{Key: "credential_id", Value: "three"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	err = repositories.ErrAPIKeyNotFound
+	w = serveCredentials(handler, "", ownerParam, gin.Param{Key: "credential_id", Value: "3"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
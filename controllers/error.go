@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/pkg/validation"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errMalformedBody = apperror.BadRequest("malformed_body", "request body is not valid JSON")
+	errInvalidID     = apperror.BadRequest("invalid_id", "Invalid ID format")
+	errInvalidCursor = apperror.BadRequest("invalid_cursor", "invalid cursor")
+)
+
+func writeError(ctx *gin.Context, err error) {
+	appErr := apperror.From(err)
+	if appErr.Kind == apperror.KindInternal {
+		slog.ErrorContext(ctx.Request.Context(), "request failed",
+			"method", ctx.Request.Method,
+			"path", ctx.Request.URL.Path,
+			"error", err,
+		)
+	}
+
+	details := problem.New(apperror.HTTPStatus(appErr.Kind), appErr.Code, appErr.Message)
+	details.Errors = appErr.Fields
+	problem.Write(ctx, details)
+}
+
+func validationError(ctx *gin.Context, validate *validation.Validator, err error) error {
+	fields, ok := validate.FieldErrors(err, ctx.GetHeader("Accept-Language"))
+	if !ok {
+		return apperror.Internal(err)
+	}
+
+	return apperror.Validation("validation_failed", "request validation failed").WithFields(fields...)
+}
+
+// bindJSON decodes and validates the request body, answering the request
+// itself when either fails.
+func bindJSON(ctx *gin.Context, validator *validation.Validator, request interface{}) bool {
+	err := ctx.ShouldBindJSON(request)
+	if err != nil {
+		writeError(ctx, errMalformedBody)
+		return false
+	}
+
+	err = validator.Struct(request)
+	if err != nil {
+		writeError(ctx, validationError(ctx, validator, err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,10 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController interface {
+	Liveness(*gin.Context)
+	Readiness(*gin.Context)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"Learn_Jenkins/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type healthControllerImpl struct {
+	readiness *health.Checker
+}
+
+func NewHealthController(readiness *health.Checker) HealthController {
+	return &healthControllerImpl{readiness: readiness}
+}
+
+func (h *healthControllerImpl) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+func (h *healthControllerImpl) Readiness(ctx *gin.Context) {
+	report := h.readiness.Run(ctx.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
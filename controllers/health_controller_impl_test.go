@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"Learn_Jenkins/health"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthController_Liveness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := NewHealthController(health.NewChecker(time.Second))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+
+	ctrl.Liveness(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestHealthController_Readiness_Ready(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checker := health.NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return nil })
+	ctrl := NewHealthController(checker)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	ctrl.Readiness(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report health.Report
+	err := json.Unmarshal(w.Body.Bytes(), &report)
+	assert.NoError(t, err)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+}
+
+func TestHealthController_Readiness_NotReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checker := health.NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	ctrl := NewHealthController(checker)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	ctrl.Readiness(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report health.Report
+	err := json.Unmarshal(w.Body.Bytes(), &report)
+	assert.NoError(t, err)
+	assert.Equal(t, health.StatusFail, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+}
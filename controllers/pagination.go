@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/services"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type pageQuery struct {
+	Limit     int
+	Offset    int
+	PageStyle bool
+}
+
+func parsePageQuery(ctx *gin.Context) (*pageQuery, error) {
+	page, err := queryInt(ctx, "page")
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := queryInt(ctx, "page_size")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := queryInt(ctx, "limit")
+	if err != nil {
+		return nil, err
+	}
+	offset, err := queryInt(ctx, "offset")
+	if err != nil {
+		return nil, err
+	}
+
+	_, hasPage := ctx.GetQuery("page")
+	_, hasPageSize := ctx.GetQuery("page_size")
+	_, hasLimit := ctx.GetQuery("limit")
+	_, hasOffset := ctx.GetQuery("offset")
+	if (hasPage || hasPageSize) && (hasLimit || hasOffset) {
+		return nil, apperror.BadRequest("invalid_query", "page/page_size cannot be combined with limit/offset")
+	}
+
+	if hasPage || hasPageSize {
+		if hasPage && page < 1 {
+			return nil, apperror.BadRequest("invalid_query", "page must be greater than 0")
+		}
+		if page == 0 {
+			page = 1
+		}
+		if pageSize == 0 {
+			pageSize = services.DefaultPageSize
+		}
+		if pageSize > services.MaxPageSize {
+			pageSize = services.MaxPageSize
+		}
+		return &pageQuery{Limit: pageSize, Offset: (page - 1) * pageSize, PageStyle: true}, nil
+	}
+	return &pageQuery{Limit: limit, Offset: offset}, nil
+}
+
+func parseSort(value string) []dto.SortField {
+	var fields []dto.SortField
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := dto.SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = dto.SortField{Field: part[1:], Desc: true}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func pageLinks(ctx *gin.Context, page *pageQuery, limit, offset int, hasMore bool) dto.PageLinks {
+	var links dto.PageLinks
+	if hasMore {
+		links.Next = pageURL(ctx, page.PageStyle, limit, offset+limit)
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links.Prev = pageURL(ctx, page.PageStyle, limit, prev)
+	}
+	return links
+}
+
+func pageURL(ctx *gin.Context, pageStyle bool, limit, offset int) string {
+	query := url.Values{}
+	for key, values := range ctx.Request.URL.Query() {
+		query[key] = values
+	}
+	if pageStyle {
+		query.Set("page", strconv.Itoa(offset/limit+1))
+		query.Set("page_size", strconv.Itoa(limit))
+	} else {
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	return ctx.Request.URL.Path + "?" + query.Encode()
+}
+
+func queryInt(ctx *gin.Context, key string) (int, error) {
+	value, ok := ctx.GetQuery(key)
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, apperror.BadRequest("invalid_query", key+" must be a non-negative integer")
+	}
+	return n, nil
+}
+
+type userCursorState struct {
+	Sort           []dto.SortField `json:"sort,omitempty"`
+	UsernamePrefix string          `json:"username_prefix,omitempty"`
+	After          dto.UserCursor  `json:"after"`
+}
+
+func cursorURL(ctx *gin.Context, token string, limit int) string {
+	query := url.Values{}
+	for key, values := range ctx.Request.URL.Query() {
+		query[key] = values
+	}
+	for _, key := range []string{"page", "page_size", "offset", "sort", "username_prefix"} {
+		query.Del(key)
+	}
+	query.Set("cursor", token)
+	query.Set("limit", strconv.Itoa(limit))
+	return ctx.Request.URL.Path + "?" + query.Encode()
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeCollection is the single way list endpoints respond, so that every
+// collection keeps the same envelope and an empty result is [] rather than
+// null.
+func writeCollection[T any](ctx *gin.Context, list *dto.ListResponse[T]) {
+	if list.Items == nil {
+		list.Items = []T{}
+	}
+	ctx.JSON(http.StatusOK, list)
+}
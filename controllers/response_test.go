@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/services"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var collectionFields = []string{"has_more", "items", "limit", "links", "offset", "total"}
+
+func decodeCollection(t *testing.T, body []byte) map[string]json.RawMessage {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("response is not a JSON object: %v", err)
+	}
+	return envelope
+}
+
+func envelopeFields(envelope map[string]json.RawMessage) []string {
+	fields := make([]string, 0, len(envelope))
+	for field := range envelope {
+		if field != "next_cursor" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestWriteCollection_NilItemsIsEmptyArray(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	writeCollection(c, &dto.ListResponse[string]{Limit: 20})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[],"total":0,"limit":20,"offset":0,"has_more":false,"links":{}}`, w.Body.String())
+}
+
+func TestUserController_FindAllUsers_CollectionContract(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name  string
+		list  *dto.UserListResponse
+		items string
+	}{
+		{name: "nil items", list: &dto.UserListResponse{Limit: 20}, items: `[]`},
+		{name: "empty items", list: &dto.UserListResponse{Items: []*dto.UserResponse{}, Limit: 20}, items: `[]`},
+		{
+			name:  "one item",
+			list:  &dto.UserListResponse{Items: []*dto.UserResponse{{ID: 1, Username: "arthur"}}, Total: 1, Limit: 20},
+			items: `[{"id":1,"username":"arthur"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := NewUserController(&fakeUserService{findAllResp: tt.list}, testCursorCodec, testValidator)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+
+			ctrl.FindAllUsers(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			envelope := decodeCollection(t, w.Body.Bytes())
+			assert.Equal(t, collectionFields, envelopeFields(envelope))
+			assert.JSONEq(t, tt.items, string(envelope["items"]))
+		})
+	}
+}
+
+func TestUserController_FindAllUsers_EmptyStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := services.NewUserService(repositories.NewMemoryUserRepository(), repositories.NewMemoryRefreshTokenRepository(), testHasher)
+	ctrl := NewUserController(svc, testCursorCodec, testValidator)
+
+	for _, target := range []string{"/users", "/users?page=3", "/users?username_prefix=zz", "/users?limit=5&offset=10"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+		ctrl.FindAllUsers(c)
+
+		assert.Equal(t, http.StatusOK, w.Code, target)
+		envelope := decodeCollection(t, w.Body.Bytes())
+		assert.Equal(t, collectionFields, envelopeFields(envelope), target)
+		assert.Equal(t, "[]", string(envelope["items"]), target)
+		assert.Equal(t, "0", string(envelope["total"]), target)
+	}
+}
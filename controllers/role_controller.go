@@ -0,0 +1,12 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type RoleController interface {
+	FindAllRoles(*gin.Context)
+	FindUserRoles(*gin.Context)
+	AssignRole(*gin.Context)
+	RevokeRole(*gin.Context)
+}
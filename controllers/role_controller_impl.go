@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"Learn_Jenkins/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type roleControllerImpl struct {
+	roleService services.RoleService
+}
+
+func NewRoleController(roleService services.RoleService) RoleController {
+	return &roleControllerImpl{roleService: roleService}
+}
+
+func (s *roleControllerImpl) FindAllRoles(ctx *gin.Context) {
+	roles, err := s.roleService.FindAllRoles(ctx.Request.Context())
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	writeCollection(ctx, roles)
+}
+
+func (s *roleControllerImpl) FindUserRoles(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	roles, err := s.roleService.FindUserRoles(ctx.Request.Context(), id)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	writeCollection(ctx, roles)
+}
+
+func (s *roleControllerImpl) AssignRole(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.roleService.AssignRole(ctx.Request.Context(), id, ctx.Param("role"))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (s *roleControllerImpl) RevokeRole(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.roleService.RevokeRole(ctx.Request.Context(), id, ctx.Param("role"))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
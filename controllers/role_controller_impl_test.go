@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/repositories"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRoleService struct {
+	roles    *dto.RoleListResponse
+	err      error
+	userID   uint
+	assigned string
+	revoked  string
+}
+
+func (f *fakeRoleService) FindAllRoles(ctx context.Context) (*dto.RoleListResponse, error) {
+	return f.roles, f.err
+}
+
+func (f *fakeRoleService) FindUserRoles(ctx context.Context, userID uint) (*dto.RoleListResponse, error) {
+	f.userID = userID
+	return f.roles, f.err
+}
+
+func (f *fakeRoleService) AssignRole(ctx context.Context, userID uint, role string) error {
+	f.userID, f.assigned = userID, role
+	return f.err
+}
+
+func (f *fakeRoleService) RevokeRole(ctx context.Context, userID uint, role string) error {
+	f.userID, f.revoked = userID, role
+	return f.err
+}
+
+func (f *fakeRoleService) Subject(ctx context.Context, principal *auth.Principal) (*authz.Subject, error) {
+	return nil, f.err
+}
+
+func (f *fakeRoleService) UserPermissions(ctx context.Context, userID uint) ([]authz.Permission, error) {
+	return nil, f.err
+}
+
+func serveRoles(handler func(*gin.Context), params ...gin.Param) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Params = params
+	handler(c)
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestRoleController_FindAllRoles(t *testing.T) {
+	fake := &fakeRoleService{roles: &dto.RoleListResponse{
+		Items: []*dto.RoleResponse{{Name: "viewer", Description: "Reads any user", Permissions: []string{"users:read_all"}}},
+		Total: 1,
+		Limit: 1,
+	}}
+
+	w := serveRoles(NewRoleController(fake).FindAllRoles)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[{"name":"viewer","description":"Reads any user","permissions":["users:read_all"]}],"total":1,"limit":1,"offset":0,"has_more":false,"links":{}}`, w.Body.String())
+}
+
+func TestRoleController_FindUserRoles_Empty(t *testing.T) {
+	fake := &fakeRoleService{roles: &dto.RoleListResponse{}}
+
+	w := serveRoles(NewRoleController(fake).FindUserRoles, gin.Param{Key: "id", Value: "7"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(7), fake.userID)
+	assert.JSONEq(t, `{"items":[],"total":0,"limit":0,"offset":0,"has_more":false,"links":{}}`, w.Body.String())
+}
+
+func TestRoleController_FindUserRoles_NotFound(t *testing.T) {
+	fake := &fakeRoleService{err: repositories.ErrUserNotFound}
+
+	w := serveRoles(NewRoleController(fake).FindUserRoles, gin.Param{Key: "id", Value: "7"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRoleController_AssignRole(t *testing.T) {
+	fake := &fakeRoleService{}
+
+	w := serveRoles(NewRoleController(fake).AssignRole, gin.Param{Key: "id", Value: "7"}, gin.Param{Key: "role", Value: "operator"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, uint(7), fake.userID)
+	assert.Equal(t, "operator", fake.assigned)
+}
+
+func TestRoleController_AssignRole_UnknownRole(t *testing.T) {
+	fake := &fakeRoleService{err: repositories.ErrRoleNotFound}
+
+	w := serveRoles(NewRoleController(fake).AssignRole, gin.Param{Key: "id", Value: "7"}, gin.Param{Key: "role", Value: "superuser"})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRoleController_RevokeRole(t *testing.T) {
+	fake := &fakeRoleService{}
+
+	w := serveRoles(NewRoleController(fake).RevokeRole, gin.Param{Key: "id", Value: "7"}, gin.Param{Key: "role", Value: "operator"})
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "operator", fake.revoked)
+}
+
+func TestRoleController_InvalidID(t *testing.T) {
+	fake := &fakeRoleService{}
+
+	w := serveRoles(NewRoleController(fake).RevokeRole, gin.Param{Key: "id", Value: "abc"}, gin.Param{Key: "role", Value: "operator"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, fake.revoked)
+}
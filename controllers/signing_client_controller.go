@@ -0,0 +1,11 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type SigningClientController interface {
+	CreateSigningClient(*gin.Context)
+	FindSigningClients(*gin.Context)
+	RevokeSigningClient(*gin.Context)
+}
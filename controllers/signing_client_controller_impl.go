@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"Learn_Jenkins/pkg/validation"
+	"Learn_Jenkins/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type signingClientControllerImpl struct {
+	signingClientService services.SigningClientService
+	validator            *validation.Validator
+}
+
+func NewSigningClientController(signingClientService services.SigningClientService, validator *validation.Validator) SigningClientController {
+	return &signingClientControllerImpl{signingClientService: signingClientService, validator: validator}
+}
+
+func (s *signingClientControllerImpl) CreateSigningClient(ctx *gin.Context) {
+	createCredential(ctx, s.validator, s.signingClientService.CreateSigningClient)
+}
+
+func (s *signingClientControllerImpl) FindSigningClients(ctx *gin.Context) {
+	findCredentials(ctx, s.signingClientService.FindSigningClients)
+}
+
+func (s *signingClientControllerImpl) RevokeSigningClient(ctx *gin.Context) {
+	revokeCredential(ctx, "signing_client_id", s.signingClientService.RevokeSigningClient)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/signature"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSigningClientService struct {
+	created  *dto.SigningClientCreatedResponse
+	clients  *dto.SigningClientListResponse
+	err      error
+	userID   uint
+	clientID uint
+	request  *dto.SigningClientRequest
+}
+
+func (f *fakeSigningClientService) CreateSigningClient(ctx context.Context, userID uint, req *dto.SigningClientRequest) (*dto.SigningClientCreatedResponse, error) {
+	f.userID, f.request = userID, req
+	return f.created, f.err
+}
+
+func (f *fakeSigningClientService) FindSigningClients(ctx context.Context, userID uint) (*dto.SigningClientListResponse, error) {
+	f.userID = userID
+	return f.clients, f.err
+}
+
+func (f *fakeSigningClientService) RevokeSigningClient(ctx context.Context, userID, id uint) error {
+	f.userID, f.clientID = userID, id
+	return f.err
+}
+
+func (f *fakeSigningClientService) VerifySignature(ctx context.Context, credentials *signature.Credentials, stringToSign string) (*auth.Principal, error) {
+	return nil, f.err
+}
+
+func (f *fakeSigningClientService) PurgeExpiredNonces(ctx context.Context) (int64, error) {
+	return 0, f.err
+}
+
+func TestSigningClientController_CreateSigningClient(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := &fakeSigningClientService{created: &dto.SigningClientCreatedResponse{
+		SigningClientResponse: dto.SigningClientResponse{ID: 2, Name: "billing", ClientID: "sc_0123456789abcdef", Scopes: []string{"users:read_all"}, CreatedAt: createdAt},
+		Secret:                "secret",
+	}}
+
+	w := serveCredentials(NewSigningClientController(fake, testValidator).CreateSigningClient, `{"name":"billing","scopes":["users:read_all"]}`, ownerParam)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"id":2,"name":"billing","client_id":"sc_0123456789abcdef","scopes":["users:read_all"],"revoked_at":null,"created_at":"2025-01-01T12:00:00Z","secret":"secret"}`, w.Body.String())
+	assert.Equal(t, uint(7), fake.userID)
+	assert.Equal(t, &dto.SigningClientRequest{Name: "billing", Scopes: []string{"users:read_all"}}, fake.request)
+}
+
+func TestSigningClientController_CreateSigningClient_ValidationError(t *testing.T) {
+	fake := &fakeSigningClientService{}
+
+	w := serveCredentials(NewSigningClientController(fake, testValidator).CreateSigningClient, `{"name":"billing","scopes":["users:*"]}`, ownerParam)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"scopes[0]"`)
+	assert.Nil(t, fake.request)
+}
+
+func TestSigningClientController_FindAndRevoke(t *testing.T) {
+	fake := &fakeSigningClientService{clients: &dto.SigningClientListResponse{}}
+	ctrl := NewSigningClientController(fake, testValidator)
+
+	w := serveCredentials(ctrl.FindSigningClients, "", ownerParam)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(7), fake.userID)
+
+	w = serveCredentials(ctrl.RevokeSigningClient, "", gin.Param{Key: "id", Value: "8"}, gin.Param{Key: "signing_client_id", Value: "2"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, uint(8), fake.userID)
+	assert.Equal(t, uint(2), fake.clientID)
+}
@@ -8,4 +8,7 @@ type UserController interface {
 	CreateUser(*gin.Context)
 	FindUserByID(*gin.Context)
 	FindAllUsers(*gin.Context)
+	UpdateUser(*gin.Context)
+	PatchUser(*gin.Context)
+	DeleteUser(*gin.Context)
 }
@@ -1,70 +1,182 @@
 package controllers
 
 import (
+	"Learn_Jenkins/domain/apperror"
 	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/cursor"
+	"Learn_Jenkins/pkg/validation"
 	"Learn_Jenkins/services"
 	"net/http"
 
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 )
 
 type userControllerImpl struct {
 	userService services.UserService
+	cursorCodec *cursor.Codec
+	validator   *validation.Validator
 }
 
-func NewUserController(userService services.UserService) UserController {
-	return &userControllerImpl{userService: userService}
+func NewUserController(userService services.UserService, cursorCodec *cursor.Codec, validator *validation.Validator) UserController {
+	return &userControllerImpl{userService: userService, cursorCodec: cursorCodec, validator: validator}
 }
 
 func (s *userControllerImpl) CreateUser(ctx *gin.Context) {
 	request := &dto.UserRequest{}
-	err := ctx.ShouldBindJSON(&request)
+	if !bindJSON(ctx, s.validator, request) {
+		return
+	}
+
+	user, err := s.userService.CreateUser(ctx.Request.Context(), request)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, user)
+}
+
+func (s *userControllerImpl) FindUserByID(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
 		return
 	}
 
-	validate := validator.New()
-	err = validate.Struct(request)
+	user, err := s.userService.FindUserByID(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeError(ctx, err)
 		return
 	}
 
-	user, err := s.userService.CreateUser(ctx, request)
+	ctx.JSON(http.StatusOK, user)
+}
+
+func (s *userControllerImpl) FindAllUsers(ctx *gin.Context) {
+	page, err := parsePageQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, user)
+	filter := &dto.UserFilter{
+		Limit:          page.Limit,
+		Offset:         page.Offset,
+		Sort:           parseSort(ctx.Query("sort")),
+		UsernamePrefix: ctx.Query("username_prefix"),
+	}
+
+	token, cursorMode := ctx.GetQuery("cursor")
+	if cursorMode {
+		if ctx.Query("page") != "" || ctx.Query("page_size") != "" || ctx.Query("offset") != "" {
+			writeError(ctx, apperror.BadRequest("invalid_query", "cursor cannot be combined with page, page_size or offset"))
+			return
+		}
+		var state userCursorState
+		err = s.cursorCodec.Decode(token, &state)
+		if err != nil {
+			writeError(ctx, errInvalidCursor)
+			return
+		}
+		filter.Sort = state.Sort
+		filter.UsernamePrefix = state.UsernamePrefix
+		filter.After = &state.After
+	}
+
+	users, err := s.userService.FindAllUsers(ctx.Request.Context(), filter)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	if users.HasMore && len(users.Items) > 0 {
+		last := users.Items[len(users.Items)-1]
+		users.NextCursor, err = s.cursorCodec.Encode(userCursorState{
+			Sort:           filter.Sort,
+			UsernamePrefix: filter.UsernamePrefix,
+			After:          dto.UserCursor{ID: last.ID, Username: last.Username},
+		})
+		if err != nil {
+			writeError(ctx, err)
+			return
+		}
+	}
+
+	if cursorMode {
+		if users.NextCursor != "" {
+			users.Links.Next = cursorURL(ctx, users.NextCursor, users.Limit)
+		}
+	} else {
+		users.Links = pageLinks(ctx, page, users.Limit, users.Offset, users.HasMore)
+	}
+	writeCollection(ctx, users)
 }
 
-func (s *userControllerImpl) FindUserByID(ctx *gin.Context) {
-	idStr := ctx.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+func (s *userControllerImpl) UpdateUser(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	request := &dto.UserRequest{}
+	if !bindJSON(ctx, s.validator, request) {
+		return
+	}
+
+	user, err := s.userService.UpdateUser(ctx.Request.Context(), id, request)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		writeError(ctx, err)
 		return
 	}
 
-	user, err := s.userService.FindUserByID(ctx, uint(id))
+	ctx.JSON(http.StatusOK, user)
+}
+
+func (s *userControllerImpl) PatchUser(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	request := &dto.UserPatchRequest{}
+	if !bindJSON(ctx, s.validator, request) {
+		return
+	}
+
+	user, err := s.userService.PatchUser(ctx.Request.Context(), id, request)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, user)
 }
 
-func (s *userControllerImpl) FindAllUsers(ctx *gin.Context) {
-	users, err := s.userService.FindAllUsers(ctx)
+func (s *userControllerImpl) DeleteUser(ctx *gin.Context) {
+	id, ok := parseID(ctx)
+	if !ok {
+		return
+	}
+
+	err := s.userService.DeleteUser(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, users)
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func parseID(ctx *gin.Context) (uint, bool) {
+	return parseIDParam(ctx, "id")
+}
+
+func parseIDParam(ctx *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 32)
+	if err != nil {
+		writeError(ctx, errInvalidID)
+		return 0, false
+	}
+	return uint(id), true
 }
@@ -2,6 +2,13 @@ package controllers
 
 import (
 	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/middlewares"
+	"Learn_Jenkins/pkg/cursor"
+	"Learn_Jenkins/pkg/password"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/pkg/requestid"
+	"Learn_Jenkins/pkg/validation"
+	"Learn_Jenkins/repositories"
 	"Learn_Jenkins/services"
 	"context"
 	"encoding/json"
@@ -13,15 +20,44 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	testCursorCodec = cursor.NewCodec([]byte("test-secret"))
+	testValidator   = newTestValidator()
+	testHasher      = newTestHasher()
+)
+
+// newTestHasher uses the cheapest bcrypt cost to keep tests fast.
+func newTestHasher() *password.Hasher {
+	hasher, err := password.NewHasher(password.Params{Algorithm: password.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		panic(err)
+	}
+	return hasher
+}
+
+func newTestValidator() *validation.Validator {
+	validator, err := validation.New()
+	if err != nil {
+		panic(err)
+	}
+	return validator
+}
+
 type fakeUserService struct {
 	createResp  *dto.UserResponse
 	createErr   error
 	findResp    *dto.UserResponse
 	findErr     error
-	findAllResp []*dto.UserResponse
+	findCtx     context.Context
+	findAllResp *dto.UserListResponse
 	findAllErr  error
+	findFilter  *dto.UserFilter
+	updateResp  *dto.UserResponse
+	updateErr   error
+	deleteErr   error
 }
 
 func (f *fakeUserService) CreateUser(ctx context.Context, req *dto.UserRequest) (*dto.UserResponse, error) {
@@ -29,24 +65,42 @@ func (f *fakeUserService) CreateUser(ctx context.Context, req *dto.UserRequest)
 }
 
 func (f *fakeUserService) FindUserByID(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	f.findCtx = ctx
 	return f.findResp, f.findErr
 }
 
-func (f *fakeUserService) FindAllUsers(ctx context.Context) ([]*dto.UserResponse, error) {
+func (f *fakeUserService) FindAllUsers(ctx context.Context, filter *dto.UserFilter) (*dto.UserListResponse, error) {
+	f.findFilter = filter
 	return f.findAllResp, f.findAllErr
 }
 
+func (f *fakeUserService) UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*dto.UserResponse, error) {
+	return f.updateResp, f.updateErr
+}
+
+func (f *fakeUserService) PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*dto.UserResponse, error) {
+	return f.updateResp, f.updateErr
+}
+
+func (f *fakeUserService) DeleteUser(ctx context.Context, id uint) error {
+	return f.deleteErr
+}
+
+func (f *fakeUserService) Authenticate(ctx context.Context, username, password string) (*dto.UserResponse, error) {
+	return f.findResp, f.findErr
+}
+
 func TestUserController_CreateUser_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	fake := &fakeUserService{
 		createResp: &dto.UserResponse{ID: 1, Username: "Arthur"},
 	}
-	ctrl := NewUserController(services.UserService(fake))
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
-	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur"}`))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur","password":"correct-Horse-battery"}`))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
@@ -63,19 +117,99 @@ func TestUserController_CreateUser_Success(t *testing.T) {
 func TestUserController_CreateUser_ValidationError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	fake := &fakeUserService{}
-	ctrl := NewUserController(services.UserService(fake))
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
 	// missing username -> validation should fail
-	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"password":"correct-Horse-battery"}`))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
 	ctrl.CreateUser(c)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "validation_failed", resp.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Status)
+	assert.Equal(t, "/", resp.Instance)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "username", resp.Errors[0].Field)
+	assert.Equal(t, "required", resp.Errors[0].Code)
+	assert.Equal(t, "username is a required field", resp.Errors[0].Message)
+}
+
+func TestUserController_CreateUser_ResponseOmitsPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := services.NewUserService(repositories.NewMemoryUserRepository(), repositories.NewMemoryRefreshTokenRepository(), testHasher)
+	ctrl := NewUserController(svc, testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur","password":"correct-Horse-battery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	ctrl.CreateUser(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":1,"username":"Arthur"}`, w.Body.String())
+}
+
+func TestUserController_CreateUser_WeakPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur","password":"alllowercaseletters"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	ctrl.CreateUser(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "password", resp.Errors[0].Field)
+	assert.Equal(t, "password", resp.Errors[0].Code)
+	assert.NotContains(t, w.Body.String(), "alllowercaseletters")
+}
+
+func TestUserController_CreateUser_TranslatedValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"admin","password":"correct-Horse-battery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "id-ID,id;q=0.9,en;q=0.8")
+	c.Request = req
+
+	ctrl.CreateUser(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "username", resp.Errors[0].Field)
+	assert.Equal(t, "not_reserved", resp.Errors[0].Code)
+	assert.Equal(t, "username sudah dicadangkan dan tidak dapat digunakan", resp.Errors[0].Message)
 }
 
 func TestUserController_CreateUser_ServiceError(t *testing.T) {
@@ -83,18 +217,25 @@ func TestUserController_CreateUser_ServiceError(t *testing.T) {
 	fake := &fakeUserService{
 		createErr: errors.New("service failure"),
 	}
-	ctrl := NewUserController(services.UserService(fake))
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
-	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur"}`))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur","password":"correct-Horse-battery"}`))
 	req.Header.Set("Content-Type", "application/json")
 	c.Request = req
 
 	ctrl.CreateUser(c)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "service failure")
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "internal_error", resp.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
 }
 
 func TestUserController_FindUserByID_Success(t *testing.T) {
@@ -102,10 +243,11 @@ func TestUserController_FindUserByID_Success(t *testing.T) {
 	fake := &fakeUserService{
 		findResp: &dto.UserResponse{ID: 1, Username: "TestUser"},
 	}
-	ctrl := NewUserController(services.UserService(fake))
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/1", nil)
 
 	// set param id
 	c.Params = gin.Params{{Key: "id", Value: "1"}}
@@ -122,10 +264,11 @@ func TestUserController_FindUserByID_Success(t *testing.T) {
 func TestUserController_FindUserByID_InvalidID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	fake := &fakeUserService{}
-	ctrl := NewUserController(services.UserService(fake))
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/abc", nil)
 
 	c.Params = gin.Params{{Key: "id", Value: "abc"}}
 	ctrl.FindUserByID(c)
@@ -136,24 +279,508 @@ func TestUserController_FindUserByID_InvalidID(t *testing.T) {
 func TestUserController_FindAllUsers_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	fake := &fakeUserService{
-		findAllResp: []*dto.UserResponse{
-			{ID: 1, Username: "User1"},
-			{ID: 2, Username: "User2"},
+		findAllResp: &dto.UserListResponse{
+			Items: []*dto.UserResponse{
+				{ID: 1, Username: "User1"},
+				{ID: 2, Username: "User2"},
+			},
+			Total: 2,
+			Limit: 20,
+		},
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	ctrl.FindAllUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.UserListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 2)
+	assert.Equal(t, "User1", resp.Items[0].Username)
+	assert.Equal(t, "User2", resp.Items[1].Username)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Empty(t, resp.Links.Next)
+	assert.Empty(t, resp.Links.Prev)
+}
+
+func TestUserController_FindAllUsers_PageQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		findAllResp: &dto.UserListResponse{
+			Items:   []*dto.UserResponse{{ID: 3, Username: "User3"}},
+			Total:   5,
+			Limit:   2,
+			Offset:  2,
+			HasMore: true,
+		},
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?page=2&page_size=2&sort=username,-id&username_prefix=User", nil)
+
+	ctrl.FindAllUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &dto.UserFilter{
+		Limit:          2,
+		Offset:         2,
+		Sort:           []dto.SortField{{Field: "username"}, {Field: "id", Desc: true}},
+		UsernamePrefix: "User",
+	}, fake.findFilter)
+
+	var resp dto.UserListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users?page=3&page_size=2&sort=username%2C-id&username_prefix=User", resp.Links.Next)
+	assert.Equal(t, "/users?page=1&page_size=2&sort=username%2C-id&username_prefix=User", resp.Links.Prev)
+}
+
+func TestUserController_FindAllUsers_LimitOffsetQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		findAllResp: &dto.UserListResponse{Total: 25, Limit: 10, Offset: 5, HasMore: true},
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?limit=10&offset=5", nil)
+
+	ctrl.FindAllUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 10, fake.findFilter.Limit)
+	assert.Equal(t, 5, fake.findFilter.Offset)
+
+	var resp dto.UserListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users?limit=10&offset=15", resp.Links.Next)
+	assert.Equal(t, "/users?limit=10&offset=0", resp.Links.Prev)
+}
+
+func TestUserController_FindAllUsers_CursorRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		findAllResp: &dto.UserListResponse{
+			Items:   []*dto.UserResponse{{ID: 4, Username: "bob"}, {ID: 2, Username: "carol"}},
+			Total:   3,
+			Limit:   2,
+			HasMore: true,
 		},
 	}
-	ctrl := NewUserController(services.UserService(fake))
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?limit=2&sort=username&username_prefix=b", nil)
 
 	ctrl.FindAllUsers(c)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var resp []*dto.UserResponse
+	var resp dto.UserListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.NextCursor)
+
+	fake.findAllResp = &dto.UserListResponse{
+		Items: []*dto.UserResponse{{ID: 9, Username: "dave"}},
+		Total: 3,
+		Limit: 2,
+	}
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?limit=2&cursor="+resp.NextCursor, nil)
+
+	ctrl.FindAllUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &dto.UserFilter{
+		Limit:          2,
+		Sort:           []dto.SortField{{Field: "username"}},
+		UsernamePrefix: "b",
+		After:          &dto.UserCursor{ID: 2, Username: "carol"},
+	}, fake.findFilter)
+
+	var last dto.UserListResponse
+	err = json.Unmarshal(w.Body.Bytes(), &last)
+	assert.NoError(t, err)
+	assert.Empty(t, last.NextCursor)
+	assert.Empty(t, last.Links.Next)
+}
+
+func TestUserController_FindAllUsers_InvalidCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	forged, err := cursor.NewCodec([]byte("other-secret")).Encode(userCursorState{After: dto.UserCursor{ID: 1}})
+	assert.NoError(t, err)
+
+	for _, target := range []string{
+		"/users?cursor=garbage",
+		"/users?cursor=" + forged,
+		"/users?offset=10&cursor=" + forged,
+	} {
+		fake := &fakeUserService{}
+		ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+		ctrl.FindAllUsers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		assert.Nil(t, fake.findFilter, target)
+	}
+}
+
+func TestUserController_FindAllUsers_CursorRejectsPageParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid, err := testCursorCodec.Encode(userCursorState{After: dto.UserCursor{ID: 1}})
+	assert.NoError(t, err)
+
+	for _, query := range []string{"page=2", "page_size=10", "offset=10"} {
+		fake := &fakeUserService{}
+		ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users?"+query+"&cursor="+valid, nil)
+
+		ctrl.FindAllUsers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), `"code":"invalid_query"`, query)
+		assert.Nil(t, fake.findFilter, query)
+	}
+}
+
+func TestUserController_FindAllUsers_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, target := range []string{
+		"/users?page=abc",
+		"/users?page=0",
+		"/users?limit=-1",
+		"/users?page=1&limit=10",
+	} {
+		fake := &fakeUserService{}
+		ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+		ctrl.FindAllUsers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		assert.Nil(t, fake.findFilter, target)
+	}
+}
+
+func TestUserController_FindAllUsers_InvalidSortField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		findAllErr: repositories.ErrInvalidSortField,
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?sort=password", nil)
+
+	ctrl.FindAllUsers(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestUserController_CreateUser_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		createErr: repositories.ErrUsernameAlreadyExists,
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"Arthur","password":"correct-Horse-battery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	ctrl.CreateUser(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "username_taken", resp.Code)
+}
+
+func TestUserController_CreateUser_MalformedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	ctrl.CreateUser(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "malformed_body", resp.Code)
+}
+
+func TestUserController_FindUserByID_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		findErr: repositories.ErrUserNotFound,
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	ctrl.FindUserByID(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "user_not_found", resp.Code)
+}
+
+func TestUserController_UpdateUser_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		updateResp: &dto.UserResponse{ID: 1, Username: "Renamed"},
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"username":"Renamed","password":"correct-Horse-battery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	ctrl.UpdateUser(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.UserResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", resp.Username)
+}
+
+func TestUserController_UpdateUser_ValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	ctrl.UpdateUser(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestUserController_UpdateUser_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		updateErr: repositories.ErrUserNotFound,
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"username":"Renamed","password":"correct-Horse-battery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "99"}}
+
+	ctrl.UpdateUser(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUserController_UpdateUser_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		updateErr: repositories.ErrUsernameAlreadyExists,
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"username":"Taken","password":"correct-Horse-battery"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	ctrl.UpdateUser(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUserController_PatchUser_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		updateResp: &dto.UserResponse{ID: 1, Username: "Patched"},
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"username":"Patched"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	ctrl.PatchUser(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.UserResponse
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Len(t, resp, 2)
-	assert.Equal(t, "User1", resp[0].Username)
-	assert.Equal(t, "User2", resp[1].Username)
+	assert.Equal(t, "Patched", resp.Username)
+}
+
+func TestUserController_PatchUser_InvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPatch, "/users/abc", nil)
+
+	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+	ctrl.PatchUser(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUserController_BodyProblems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := NewUserController(services.UserService(&fakeUserService{}), testCursorCodec, testValidator)
+	handlers := map[string]gin.HandlerFunc{
+		"create": ctrl.CreateUser,
+		"update": ctrl.UpdateUser,
+		"patch":  ctrl.PatchUser,
+	}
+	bodies := map[string]struct {
+		status int
+		code   string
+	}{
+		`{"username":`:       {http.StatusBadRequest, "malformed_body"},
+		`{"username":"a b"}`: {http.StatusUnprocessableEntity, "validation_failed"},
+	}
+
+	for name, handler := range handlers {
+		for body, want := range bodies {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			handler(c)
+
+			assert.Equal(t, want.status, w.Code, "%s %s", name, body)
+			assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), "%s %s", name, body)
+			var resp problem.Details
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, want.code, resp.Code, "%s %s", name, body)
+		}
+	}
+}
+
+func TestUserController_DeleteUser_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	ctrl.DeleteUser(c)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestUserController_DeleteUser_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{
+		deleteErr: repositories.ErrUserNotFound,
+	}
+	ctrl := NewUserController(services.UserService(fake), testCursorCodec, testValidator)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/99", nil)
+
+	c.Params = gin.Params{{Key: "id", Value: "99"}}
+	ctrl.DeleteUser(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUserController_PropagatesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := &fakeUserService{findErr: repositories.ErrUserNotFound}
+	router := gin.New()
+	router.Use(middlewares.RequestID())
+	router.GET("/users/:id", NewUserController(fake, testCursorCodec, testValidator).FindUserByID)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set(requestid.Header, "req-99")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-99", requestid.FromContext(fake.findCtx))
+	assert.Equal(t, "req-99", w.Header().Get(requestid.Header))
+
+	var details problem.Details
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, "req-99", details.RequestID)
 }
@@ -0,0 +1,41 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_IsMatchesKindAndCode(t *testing.T) {
+	sentinel := NotFound("user_not_found", "user not found")
+
+	assert.ErrorIs(t, NotFound("user_not_found", "user 7 not found"), sentinel)
+	assert.ErrorIs(t, fmt.Errorf("lookup: %w", sentinel), sentinel)
+	assert.NotErrorIs(t, Conflict("user_not_found", "user not found"), sentinel)
+	assert.NotErrorIs(t, NotFound("role_not_found", "role not found"), sentinel)
+}
+
+func TestFrom(t *testing.T) {
+	conflict := Conflict("username_taken", "username already exists")
+	assert.Same(t, conflict, From(fmt.Errorf("wrapped: %w", conflict)))
+
+	cause := errors.New("connection refused")
+	internal := From(cause)
+	assert.Equal(t, KindInternal, internal.Kind)
+	assert.Equal(t, CodeInternal, internal.Code)
+	assert.NotContains(t, internal.Message, "connection refused")
+	assert.ErrorIs(t, internal, cause)
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, HTTPStatus(KindBadRequest))
+	assert.Equal(t, http.StatusUnprocessableEntity, HTTPStatus(KindValidation))
+	assert.Equal(t, http.StatusNotFound, HTTPStatus(KindNotFound))
+	assert.Equal(t, http.StatusConflict, HTTPStatus(KindConflict))
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(KindUnauthorized))
+	assert.Equal(t, http.StatusForbidden, HTTPStatus(KindForbidden))
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus(KindInternal))
+}
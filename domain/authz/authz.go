@@ -0,0 +1,200 @@
+// Package authz holds the role-based access control rules: the permissions,
+// the roles seeded by default and the policy that decides, without any HTTP
+// or storage involved, whether a caller may perform an action.
+package authz
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"sort"
+)
+
+type Permission string
+
+const (
+	PermUsersCreate          Permission = "users:create"
+	PermUsersReadAll         Permission = "users:read_all"
+	PermUsersUpdate          Permission = "users:update"
+	PermUsersDelete          Permission = "users:delete"
+	PermRolesManage          Permission = "roles:manage"
+	PermAPIKeysManage        Permission = "api_keys:manage"
+	PermSigningClientsManage Permission = "signing_clients:manage"
+)
+
+// Permissions lists every permission, which are also the scopes an API key
+// can be limited to.
+var Permissions = []Permission{PermAPIKeysManage, PermRolesManage, PermSigningClientsManage, PermUsersCreate, PermUsersDelete, PermUsersReadAll, PermUsersUpdate}
+
+func (p Permission) Valid() bool {
+	for _, permission := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+)
+
+type RoleDefinition struct {
+	Name        string
+	Description string
+	Permissions []Permission
+}
+
+// DefaultRoles is the catalogue seeded by migrations 000004 to 000006; the
+// in-memory storage starts from it as well.
+var DefaultRoles = []RoleDefinition{
+	{
+		Name:        RoleAdmin,
+		Description: "Full access, including creating users and managing roles",
+		Permissions: Permissions,
+	},
+	{
+		Name:        RoleOperator,
+		Description: "Reads and updates any user",
+		Permissions: []Permission{PermUsersReadAll, PermUsersUpdate},
+	},
+	{
+		Name:        RoleViewer,
+		Description: "Reads any user",
+		Permissions: []Permission{PermUsersReadAll},
+	},
+}
+
+var ErrForbidden = apperror.Forbidden("forbidden", "you are not allowed to perform this action")
+
+type Action string
+
+const (
+	ActionCreateUser           Action = "users.create"
+	ActionReadUser             Action = "users.read"
+	ActionListUsers            Action = "users.list"
+	ActionUpdateUser           Action = "users.update"
+	ActionDeleteUser           Action = "users.delete"
+	ActionListRoles            Action = "roles.list"
+	ActionReadUserRoles        Action = "roles.read_user"
+	ActionManageUserRole       Action = "roles.manage_user"
+	ActionManageAPIKeys        Action = "api_keys.manage"
+	ActionManageSigningClients Action = "signing_clients.manage"
+)
+
+// Subject is the caller being authorized. Delegated is set when the caller
+// authenticated with a machine credential, an API key or a signed request,
+// rather than as the user in person.
+type Subject struct {
+	UserID      uint
+	Permissions []Permission
+	Delegated   bool
+}
+
+func (s *Subject) Has(permission Permission) bool {
+	for _, p := range s.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource is what the action applies to. UserID is the user the request
+// targets, or zero when it does not target one. Permissions are the ones the
+// target user holds, loaded when they are not the subject.
+type Resource struct {
+	UserID      uint
+	Permissions []Permission
+}
+
+// Rule reports whether subject may act on resource.
+type Rule func(subject *Subject, resource Resource) bool
+
+// Require allows subjects holding permission.
+func Require(permission Permission) Rule {
+	return func(subject *Subject, _ Resource) bool {
+		return subject.Has(permission)
+	}
+}
+
+// SelfOr allows subjects acting on their own user, and anyone holding
+// permission.
+func SelfOr(permission Permission) Rule {
+	return func(subject *Subject, resource Resource) bool {
+		return (resource.UserID != 0 && resource.UserID == subject.UserID) || subject.Has(permission)
+	}
+}
+
+// Outranking applies rule only to subjects acting on their own user or
+// holding every permission the target user holds, so that taking over an
+// account never grants more than the subject already has.
+func Outranking(rule Rule) Rule {
+	return func(subject *Subject, resource Resource) bool {
+		if resource.UserID == 0 || resource.UserID != subject.UserID {
+			for _, permission := range resource.Permissions {
+				if !subject.Has(permission) {
+					return false
+				}
+			}
+		}
+		return rule(subject, resource)
+	}
+}
+
+// Interactive applies rule only to subjects that are not Delegated, so that
+// a leaked machine credential cannot be used to mint new ones.
+func Interactive(rule Rule) Rule {
+	return func(subject *Subject, resource Resource) bool {
+		return !subject.Delegated && rule(subject, resource)
+	}
+}
+
+// Policy maps each action to its rule. Actions without a rule are denied.
+type Policy map[Action]Rule
+
+// DefaultPolicy lets every user read and update their own account; anything
+// touching other users or roles needs a permission. Updating an account
+// changes its username or password, so machine credentials cannot do it: a
+// key that could reset its owner's password could log in and mint more keys.
+// Nor can it grant more than the caller has: an operator who could reset an
+// admin's password could log in as that admin.
+func DefaultPolicy() Policy {
+	return Policy{
+		ActionCreateUser:           Require(PermUsersCreate),
+		ActionReadUser:             SelfOr(PermUsersReadAll),
+		ActionListUsers:            Require(PermUsersReadAll),
+		ActionUpdateUser:           Interactive(Outranking(SelfOr(PermUsersUpdate))),
+		ActionDeleteUser:           Require(PermUsersDelete),
+		ActionListRoles:            Require(PermRolesManage),
+		ActionReadUserRoles:        SelfOr(PermRolesManage),
+		ActionManageUserRole:       Require(PermRolesManage),
+		ActionManageAPIKeys:        Interactive(SelfOr(PermAPIKeysManage)),
+		ActionManageSigningClients: Interactive(SelfOr(PermSigningClientsManage)),
+	}
+}
+
+// Authorize returns ErrForbidden unless the rule for action allows subject to
+// act on resource.
+func (p Policy) Authorize(subject *Subject, action Action, resource Resource) error {
+	rule, ok := p[action]
+	if !ok || subject == nil || !rule(subject, resource) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// PermissionNames returns the sorted, de-duplicated union of permissions.
+func PermissionNames(permissions []Permission) []string {
+	seen := map[Permission]struct{}{}
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if _, ok := seen[permission]; ok {
+			continue
+		}
+		seen[permission] = struct{}{}
+		names = append(names, string(permission))
+	}
+	sort.Strings(names)
+	return names
+}
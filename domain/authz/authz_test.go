@@ -0,0 +1,132 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func roleSubject(userID uint, role string) *Subject {
+	for _, definition := range DefaultRoles {
+		if definition.Name == role {
+			return &Subject{UserID: userID, Permissions: definition.Permissions}
+		}
+	}
+	return &Subject{UserID: userID}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+	self := Resource{UserID: 7}
+	other := Resource{UserID: 8}
+
+	tests := []struct {
+		role     string
+		action   Action
+		resource Resource
+		allowed  bool
+	}{
+		{"", ActionReadUser, self, true},
+		{"", ActionReadUser, other, false},
+		{"", ActionUpdateUser, self, true},
+		{"", ActionUpdateUser, other, false},
+		{"", ActionListUsers, Resource{}, false},
+		{"", ActionCreateUser, Resource{}, false},
+		{"", ActionDeleteUser, self, false},
+		{"", ActionReadUserRoles, self, true},
+		{"", ActionManageUserRole, self, false},
+		{"", ActionManageAPIKeys, self, true},
+		{"", ActionManageAPIKeys, other, false},
+		{"", ActionManageSigningClients, self, true},
+		{"", ActionManageSigningClients, other, false},
+
+		{RoleViewer, ActionReadUser, other, true},
+		{RoleViewer, ActionListUsers, Resource{}, true},
+		{RoleViewer, ActionUpdateUser, other, false},
+		{RoleViewer, ActionCreateUser, Resource{}, false},
+
+		{RoleOperator, ActionUpdateUser, other, true},
+		{RoleOperator, ActionDeleteUser, other, false},
+		{RoleOperator, ActionCreateUser, Resource{}, false},
+		{RoleOperator, ActionListRoles, Resource{}, false},
+
+		{RoleAdmin, ActionCreateUser, Resource{}, true},
+		{RoleAdmin, ActionDeleteUser, other, true},
+		{RoleAdmin, ActionListRoles, Resource{}, true},
+		{RoleAdmin, ActionReadUserRoles, other, true},
+		{RoleAdmin, ActionManageUserRole, other, true},
+		{RoleAdmin, ActionManageAPIKeys, other, true},
+		{RoleAdmin, ActionManageSigningClients, other, true},
+	}
+
+	for _, tt := range tests {
+		err := policy.Authorize(roleSubject(7, tt.role), tt.action, tt.resource)
+		if tt.allowed {
+			assert.NoError(t, err, "%q %s %+v", tt.role, tt.action, tt.resource)
+		} else {
+			assert.ErrorIs(t, err, ErrForbidden, "%q %s %+v", tt.role, tt.action, tt.resource)
+		}
+	}
+}
+
+func TestPolicy_DeniesUnknownActionsAndMissingSubjects(t *testing.T) {
+	policy := DefaultPolicy()
+
+	assert.ErrorIs(t, policy.Authorize(roleSubject(7, RoleAdmin), "users.export", Resource{}), ErrForbidden)
+	assert.ErrorIs(t, policy.Authorize(nil, ActionReadUser, Resource{UserID: 7}), ErrForbidden)
+}
+
+func TestInteractive_DeniesDelegatedSubjects(t *testing.T) {
+	policy := DefaultPolicy()
+	subject := roleSubject(7, RoleAdmin)
+	subject.Delegated = true
+
+	assert.ErrorIs(t, policy.Authorize(subject, ActionManageAPIKeys, Resource{UserID: 7}), ErrForbidden)
+	assert.ErrorIs(t, policy.Authorize(subject, ActionManageSigningClients, Resource{UserID: 7}), ErrForbidden)
+	assert.NoError(t, policy.Authorize(subject, ActionDeleteUser, Resource{UserID: 8}))
+}
+
+func TestPolicy_APIKeyWithNoScopesCannotPatchItsOwner(t *testing.T) {
+	policy := DefaultPolicy()
+	subjects := map[string]*Subject{
+		"no scopes":    {UserID: 7, Delegated: true},
+		"read-only":    {UserID: 7, Permissions: []Permission{PermUsersReadAll}, Delegated: true},
+		"users:update": {UserID: 7, Permissions: []Permission{PermUsersUpdate}, Delegated: true},
+	}
+
+	for name, subject := range subjects {
+		assert.ErrorIs(t, policy.Authorize(subject, ActionUpdateUser, Resource{UserID: 7}), ErrForbidden, name)
+		assert.ErrorIs(t, policy.Authorize(subject, ActionUpdateUser, Resource{UserID: 8}), ErrForbidden, name)
+		assert.NoError(t, policy.Authorize(subject, ActionReadUser, Resource{UserID: 7}), name)
+	}
+}
+
+func TestPolicy_OperatorCannotUpdateAdmin(t *testing.T) {
+	policy := DefaultPolicy()
+	target := func(userID uint, role string) Resource {
+		return Resource{UserID: userID, Permissions: roleSubject(userID, role).Permissions}
+	}
+
+	assert.ErrorIs(t, policy.Authorize(roleSubject(7, RoleOperator), ActionUpdateUser, target(8, RoleAdmin)), ErrForbidden)
+	assert.NoError(t, policy.Authorize(roleSubject(7, RoleOperator), ActionUpdateUser, target(8, RoleOperator)))
+	assert.NoError(t, policy.Authorize(roleSubject(7, RoleOperator), ActionUpdateUser, target(8, RoleViewer)))
+	assert.NoError(t, policy.Authorize(roleSubject(7, RoleAdmin), ActionUpdateUser, target(8, RoleAdmin)))
+	assert.NoError(t, policy.Authorize(roleSubject(7, ""), ActionUpdateUser, target(7, RoleAdmin)), "users may always update themselves")
+}
+
+func TestPermission_Valid(t *testing.T) {
+	assert.True(t, PermUsersReadAll.Valid())
+	assert.False(t, Permission("users:*").Valid())
+}
+
+func TestSelfOr_ZeroUserIsNotSelf(t *testing.T) {
+	rule := SelfOr(PermUsersReadAll)
+
+	assert.False(t, rule(&Subject{}, Resource{}))
+}
+
+func TestPermissionNames(t *testing.T) {
+	names := PermissionNames([]Permission{PermUsersUpdate, PermUsersReadAll, PermUsersUpdate})
+
+	assert.Equal(t, []string{"users:read_all", "users:update"}, names)
+}
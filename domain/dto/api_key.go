@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type APIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Scopes are the permissions the key may use, among those its owner
+	// holds. Without any, the key can only act on its owner's own account.
+	Scopes    []string   `json:"scopes" validate:"max=16,dive,permission"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty,gt"`
+}
+
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponse is the only response that carries the key itself.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyListResponse = ListResponse[*APIKeyResponse]
@@ -0,0 +1,19 @@
+package dto
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	// Password is capped so oversized input cannot make hashing expensive.
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenResponse follows the OAuth 2.0 token response (RFC 6749, section 5.1).
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// ListResponse is the envelope returned by every list endpoint. Items is
+// always serialized as an array, never null.
+type ListResponse[T any] struct {
+	Items      []T       `json:"items"`
+	Total      int64     `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	HasMore    bool      `json:"has_more"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Links      PageLinks `json:"links"`
+}
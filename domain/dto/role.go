@@ -0,0 +1,9 @@
+package dto
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleListResponse = ListResponse[*RoleResponse]
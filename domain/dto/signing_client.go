@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type SigningClientRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Scopes are the permissions the client may use, among those its owner
+	// holds. Without any, the client can only act on its owner's account.
+	Scopes []string `json:"scopes" validate:"max=16,dive,permission"`
+}
+
+type SigningClientResponse struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	ClientID  string     `json:"client_id"`
+	Scopes    []string   `json:"scopes"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SigningClientCreatedResponse is the only response that carries the secret.
+type SigningClientCreatedResponse struct {
+	SigningClientResponse
+	Secret string `json:"secret"`
+}
+
+type SigningClientListResponse = ListResponse[*SigningClientResponse]
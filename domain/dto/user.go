@@ -1,10 +1,42 @@
 package dto
 
+import "Learn_Jenkins/domain/model"
+
 type UserRequest struct {
-	Username string `json:"username" validate:"required"`
+	Username string `json:"username" validate:"required,min=3,max=32,username,not_reserved"`
+	Password string `json:"password" validate:"required,min=12,password"`
+	// PasswordHash is set by the service from Password before the request
+	// reaches the repository, which never sees the plain password.
+	PasswordHash model.PasswordHash `json:"-" validate:"-"`
+}
+
+type UserPatchRequest struct {
+	Username     *string             `json:"username" validate:"omitempty,min=3,max=32,username,not_reserved"`
+	Password     *string             `json:"password" validate:"omitempty,min=12,password"`
+	PasswordHash *model.PasswordHash `json:"-" validate:"-"`
 }
 
 type UserResponse struct {
 	ID       uint   `json:"id"`
 	Username string `json:"username"`
 }
+
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+type UserCursor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+type UserFilter struct {
+	Limit          int
+	Offset         int
+	Sort           []SortField
+	UsernamePrefix string
+	After          *UserCursor
+}
+
+type UserListResponse = ListResponse[*UserResponse]
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// APIKey is a long-lived credential owned by a user. Prefix is the public
+// part of the key used to look it up; only the SHA-256 of the whole key is
+// kept.
+type APIKey struct {
+	Credential
+	Prefix     string `gorm:"not null;unique"`
+	KeyHash    string `gorm:"not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
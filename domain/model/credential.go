@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Credential holds what the machine credentials a user owns have in common,
+// API keys and signing clients: they are listed per owner and revoked rather
+// than deleted. Scopes is a space-separated list of the permissions the
+// credential is limited to.
+type Credential struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null"`
+	Name      string `gorm:"not null"`
+	Scopes    string `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// Base returns the shared fields, so that storage can handle both kinds of
+// credentials alike.
+func (c *Credential) Base() *Credential {
+	return c
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// RefreshToken is a stored refresh token. Only the SHA-256 of the token is
+// kept. Every token issued by rotating another one shares its FamilyID, so a
+// whole login session can be revoked at once.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null"`
+	FamilyID  string    `gorm:"not null"`
+	TokenHash string    `gorm:"not null;unique"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"not null"`
+}
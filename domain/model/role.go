@@ -0,0 +1,20 @@
+package model
+
+type Role struct {
+	ID          uint         `gorm:"primaryKey"`
+	Name        string       `gorm:"not null;unique"`
+	Description string       `gorm:"not null"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+}
+
+type Permission struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"not null;unique"`
+	Description string `gorm:"not null"`
+}
+
+// UserRole assigns a role to a user.
+type UserRole struct {
+	UserID uint `gorm:"primaryKey"`
+	RoleID uint `gorm:"primaryKey"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// SigningClient holds the shared secret a partner signs its requests with.
+// ClientID is public and names the client in each request. Checking a
+// signature needs the secret itself, so rather than a hash Secret holds it
+// encrypted with the signing encryption key and bound to ClientID.
+type SigningClient struct {
+	Credential
+	ClientID string `gorm:"not null;unique"`
+	Secret   string `gorm:"not null"`
+}
+
+// SignatureNonce records a nonce already used by a client, until the
+// request it came with could no longer be accepted anyway.
+type SignatureNonce struct {
+	ClientID  string    `gorm:"primaryKey"`
+	Nonce     string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
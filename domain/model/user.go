@@ -1,6 +1,39 @@
 package model
 
+import (
+	"database/sql/driver"
+	"log/slog"
+)
+
 type User struct {
-	ID       uint   `gorm:"primaryKey"`
-	Username string `gorm:"not null;unique"`
+	ID           uint         `gorm:"primaryKey"`
+	Username     string       `gorm:"not null;unique"`
+	PasswordHash PasswordHash `gorm:"not null"`
+}
+
+// PasswordHash is an encoded password hash. It prints, logs and marshals as
+// a placeholder so it cannot leak through fmt, slog or SQL logs; convert it
+// with string() where the real value is needed.
+type PasswordHash string
+
+const redacted = "[REDACTED]"
+
+func (PasswordHash) String() string {
+	return redacted
+}
+
+func (PasswordHash) GoString() string {
+	return redacted
+}
+
+func (PasswordHash) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+func (PasswordHash) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+func (h PasswordHash) Value() (driver.Value, error) {
+	return string(h), nil
 }
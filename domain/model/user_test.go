@@ -0,0 +1,32 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordHash_IsRedacted(t *testing.T) {
+	user := User{ID: 1, Username: "arthur", PasswordHash: "$argon2id$secret"}
+
+	var logs bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logs, nil)).Info("user", "user", user, "hash", user.PasswordHash)
+	encoded, err := json.Marshal(user)
+	assert.NoError(t, err)
+	value, err := user.PasswordHash.Value()
+	assert.NoError(t, err)
+
+	for _, out := range []string{
+		fmt.Sprint(user), fmt.Sprintf("%+v", user), fmt.Sprintf("%#v", user), fmt.Sprintf("%s", user.PasswordHash),
+		logs.String(), string(encoded),
+	} {
+		assert.NotContains(t, out, "secret")
+		assert.Contains(t, out, "[REDACTED]")
+	}
+	assert.Equal(t, "$argon2id$secret", value)
+	assert.Equal(t, "$argon2id$secret", string(user.PasswordHash))
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"Learn_Jenkins/migrations"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+func ReadyCheck(ready func() bool) CheckFunc {
+	return func(ctx context.Context) error {
+		if !ready() {
+			return errors.New("server is not accepting traffic")
+		}
+		return nil
+	}
+}
+
+func DatabaseCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+func MigrationCheck(migrator *migrations.Migrator) CheckFunc {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("schema is %d migration(s) behind", len(pending))
+		}
+		return nil
+	}
+}
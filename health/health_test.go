@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_AllHealthy(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return nil })
+	checker.Add("server", ReadyCheck(func() bool { return true }))
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+	assert.Empty(t, report.Checks["database"].Error)
+}
+
+func TestChecker_FailingCheck(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Add("server", ReadyCheck(func() bool { return false }))
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+	assert.Equal(t, StatusFail, report.Checks["server"].Status)
+}
+
+func TestChecker_TimesOutSlowChecks(t *testing.T) {
+	checker := NewChecker(20 * time.Millisecond)
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+	assert.GreaterOrEqual(t, report.Checks["slow"].LatencyMs, float64(20))
+}
@@ -3,50 +3,323 @@ package main
 import (
 	"Learn_Jenkins/config"
 	"Learn_Jenkins/controllers"
-	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/health"
 	"Learn_Jenkins/middlewares"
+	"Learn_Jenkins/migrations"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/cursor"
+	"Learn_Jenkins/pkg/logging"
+	"Learn_Jenkins/pkg/metrics"
+	"Learn_Jenkins/pkg/password"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/pkg/token"
+	"Learn_Jenkins/pkg/tracing"
+	"Learn_Jenkins/pkg/validation"
 	"Learn_Jenkins/repositories"
 	"Learn_Jenkins/routes"
+	"Learn_Jenkins/server"
 	"Learn_Jenkins/services"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
-	_ = godotenv.Load()
-	port := os.Getenv("PORT")
-	db, err := config.InitDatabase()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile, ".env")
+	if err != nil {
+		// The logger is configured from cfg, so report this one as plain text.
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level)
 	if err != nil {
 		panic(err)
 	}
+	slog.SetDefault(logger)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		panic(err)
+	}
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
 
-	fmt.Println("Connected to database")
+	var (
+		userRepository          repositories.UserRepository
+		refreshTokenRepository  repositories.RefreshTokenRepository
+		roleRepository          repositories.RoleRepository
+		apiKeyRepository        repositories.APIKeyRepository
+		signingClientRepository repositories.SigningClientRepository
+		db                      *gorm.DB
+		migrator                *migrations.Migrator
+	)
+	if cfg.Storage.Backend == config.StorageMemory {
+		if flag.Arg(0) == "migrate" {
+			slog.Error("migrate needs STORAGE=database")
+			os.Exit(1)
+		}
+		slog.Warn("using in-memory storage, users are lost on restart")
+		userRepository = repositories.NewMemoryUserRepository()
+		refreshTokenRepository = repositories.NewMemoryRefreshTokenRepository()
+		roleRepository = repositories.NewMemoryRoleRepository(userRepository)
+		apiKeyRepository = repositories.NewMemoryAPIKeyRepository(userRepository)
+		signingClientRepository = repositories.NewMemorySigningClientRepository(userRepository)
+	} else {
+		db, migrator = initDatabase(cfg.Database)
+		userRepository = repositories.NewUserRepository(db)
+		refreshTokenRepository = repositories.NewRefreshTokenRepository(db)
+		roleRepository = repositories.NewRoleRepository(db)
+		apiKeyRepository = repositories.NewAPIKeyRepository(db)
+		signingClientRepository = repositories.NewSigningClientRepository(db)
+	}
+
+	hasher, err := password.NewHasher(cfg.Password.Params())
+	if err != nil {
+		panic(err)
+	}
+	userService := services.NewTracingUserService(services.NewUserService(userRepository, refreshTokenRepository, hasher))
+	cursorCodec := cursor.NewCodec([]byte(cfg.Cursor.Secret))
+	if cfg.Cursor.Secret == "" {
+		slog.Warn("CURSOR_SECRET is not set, using a random key: cursors will not survive restarts")
+		cursorCodec, err = cursor.NewRandomCodec()
+		if err != nil {
+			panic(err)
+		}
+	}
+	validator, err := validation.New()
+	if err != nil {
+		panic(err)
+	}
+	userController := controllers.NewUserController(userService, cursorCodec, validator)
+	roleService := services.NewRoleService(roleRepository)
+	roleController := controllers.NewRoleController(roleService)
+	if err := bootstrapUser(context.Background(), userService, userRepository, roleService, validator, cfg.Auth); err != nil {
+		panic(err)
+	}
 
-	db.AutoMigrate(&model.User{})
-	userRepository := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepository)
-	userController := controllers.NewUserController(userService)
-	router := gin.Default()
-	router.Use(middlewares.HandlePanic())
+	accessTokens := token.NewManager([]byte(cfg.Auth.JWTSecret), cfg.Auth.Issuer, cfg.Auth.AccessTokenTTL)
+	if cfg.Auth.JWTSecret == "" {
+		slog.Warn("AUTH_JWT_SECRET is not set, using a random key: access tokens will not survive restarts")
+		accessTokens, err = token.NewRandomManager(cfg.Auth.Issuer, cfg.Auth.AccessTokenTTL)
+		if err != nil {
+			panic(err)
+		}
+	}
+	authService := services.NewAuthService(userService, refreshTokenRepository, accessTokens, cfg.Auth.RefreshTokenTTL)
+	authController := controllers.NewAuthController(authService, validator)
+	proxies, err := cfg.Auth.Proxies()
+	if err != nil {
+		panic(err)
+	}
+	apiKeyService := services.NewAPIKeyService(apiKeyRepository)
+	apiKeyController := controllers.NewAPIKeyController(apiKeyService, validator)
+	signingSecrets, err := cfg.Auth.SigningSealer()
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Auth.SigningEncryptionKey == "" {
+		slog.Warn("AUTH_SIGNING_ENCRYPTION_KEY is not set, using a random key: signing clients will not survive restarts")
+	}
+	signingClientService := services.NewSigningClientService(signingClientRepository, signingSecrets, cfg.Auth.SignatureMaxSkew)
+	signingClientController := controllers.NewSigningClientController(signingClientService, validator)
+	authenticate := middlewares.Authenticate(
+		auth.TrustedHeader(cfg.Auth.IdentityHeader, proxies),
+		auth.BearerToken(authService),
+		auth.APIKey(apiKeyService),
+		auth.SignedRequest(signingClientService, int64(cfg.Auth.SignatureMaxBody)),
+	)
+	authorize := middlewares.Authorize(authz.DefaultPolicy(), roleService)
+	router := gin.New()
+	// Only the proxies trusted with the identity header may set the client
+	// IP that is logged.
+	trustedProxies := make([]string, 0, len(proxies))
+	for _, proxy := range proxies {
+		trustedProxies = append(trustedProxies, proxy.String())
+	}
+	err = router.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		panic(err)
+	}
+	var reporter middlewares.FileErrorReporter
+	if cfg.Recovery.PanicReportFile != "" {
+		reporter, err = middlewares.NewFileErrorReporter(cfg.Recovery.PanicReportFile)
+		if err != nil {
+			panic(err)
+		}
+	}
+	router.Use(middlewares.RequestID(), middlewares.Tracing(), middlewares.AccessLog(logger), middlewares.Metrics(), middlewares.HandlePanic(reporter))
 	router.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Path not found"})
+		problem.Write(c, problem.New(http.StatusNotFound, "route_not_found", "Path not found"))
 	})
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "Simple Backend for Learn Jenkins"})
 	})
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
-	route := routes.NewRoute(userController, router)
+	var srv *server.Server
+	readiness := health.NewChecker(cfg.Server.ReadinessTimeout)
+	readiness.Add("server", health.ReadyCheck(func() bool { return srv != nil && srv.Ready() }))
+	if db != nil {
+		readiness.Add("database", health.DatabaseCheck(db))
+		readiness.Add("migrations", health.MigrationCheck(migrator))
+	}
+	healthController := controllers.NewHealthController(readiness)
+
+	route := routes.NewRoute(userController, healthController, authController, roleController, apiKeyController, signingClientController, authenticate, authorize, router)
 	route.Run()
-	router.Run(":" + port)
 
+	srv = server.New(fmt.Sprintf(":%d", cfg.Server.Port), router, cfg.Server.ShutdownTimeout, cfg.Server.DrainDelay)
+	if db != nil {
+		srv.OnShutdown(func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		})
+	}
+
+	if reporter != nil {
+		srv.OnShutdown(func(context.Context) error {
+			return reporter.Close()
+		})
+	}
+
+	purgeCtx, stopPurging := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+	go func() {
+		defer close(purgeDone)
+		purgeNonces(purgeCtx, signingClientService, cfg.Auth.SignatureMaxSkew)
+	}()
+	srv.OnShutdown(func(context.Context) error {
+		stopPurging()
+		<-purgeDone
+		return nil
+	})
+
+	// Hooks run once in-flight requests have finished, so every span has
+	// ended by the time the exporter is flushed.
+	srv.OnShutdown(shutdownTracing)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := srv.Run(ctx); err != nil {
+		slog.Error("server stopped with error", "error", err)
+		os.Exit(1)
+	}
 }
 
+// purgeNonces forgets expired signed request nonces every interval until ctx
+// is done. Nonces expire one clock skew after their request, so purging as
+// often keeps the table at about two windows of traffic.
+func purgeNonces(ctx context.Context, signingClients services.SigningClientService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		purged, err := signingClients.PurgeExpiredNonces(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "failed to purge expired signature nonces", "error", err)
+		}
+		if purged > 0 {
+			slog.DebugContext(ctx, "purged expired signature nonces", "count", purged)
+		}
+	}
+}
 
+// bootstrapUser creates the configured first account unless a user with that
+// name already exists, and makes sure it holds the admin role so that it can
+// create the other users.
+func bootstrapUser(ctx context.Context, userService services.UserService, userRepository repositories.UserRepository, roleService services.RoleService, validator *validation.Validator, cfg config.AuthConfig) error {
+	if cfg.BootstrapUsername == "" {
+		return nil
+	}
+	req := &dto.UserRequest{Username: cfg.BootstrapUsername, Password: cfg.BootstrapPassword}
+	if err := validator.Struct(req); err != nil {
+		return fmt.Errorf("invalid bootstrap user: %w", err)
+	}
+	var userID uint
+	user, err := userService.CreateUser(ctx, req)
+	switch {
+	case errors.Is(err, repositories.ErrUsernameAlreadyExists):
+		existing, err := userRepository.FindUserByUsername(ctx, cfg.BootstrapUsername)
+		if err != nil {
+			return fmt.Errorf("find bootstrap user: %w", err)
+		}
+		userID = existing.ID
+	case err != nil:
+		return fmt.Errorf("create bootstrap user: %w", err)
+	default:
+		slog.Info("created bootstrap user", "user_id", user.ID, "username", user.Username)
+		userID = user.ID
+	}
+	if err := roleService.AssignRole(ctx, userID, authz.RoleAdmin); err != nil {
+		return fmt.Errorf("grant admin role to bootstrap user: %w", err)
+	}
+	return nil
+}
 
+func initDatabase(cfg config.DatabaseConfig) (*gorm.DB, *migrations.Migrator) {
+	db, err := config.InitDatabase(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	slog.Info("connected to database", "driver", cfg.Driver)
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic(err)
+	}
+	migrator, err := migrations.New(sqlDB, cfg.Driver)
+	if err != nil {
+		panic(err)
+	}
 
+	if flag.Arg(0) == "migrate" {
+		err := runMigrate(context.Background(), migrator, flag.Args()[1:])
+		_ = sqlDB.Close()
+		if err != nil {
+			slog.Error("migration failed", "error", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	if len(pending) > 0 {
+		slog.Error("database schema is behind, run \"migrate up\" first", "pending", len(pending), "latest", migrator.Latest())
+		os.Exit(1)
+	}
+	return db, migrator
+}
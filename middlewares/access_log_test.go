@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Learn_Jenkins/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := slog.New(requestid.NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(logger))
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		c.Status(http.StatusServiceUnavailable)
+	})
+	router.NoRoute(func(c *gin.Context) {
+		c.String(http.StatusNotFound, "not found")
+	})
+
+	tests := []struct {
+		path   string
+		route  string
+		status int
+		bytes  float64
+		level  string
+	}{
+		{path: "/users/7", route: "/users/:id", status: http.StatusOK, bytes: 5, level: "INFO"},
+		{path: "/fail", route: "/fail", status: http.StatusServiceUnavailable, level: "ERROR"},
+		{path: "/missing", route: "", status: http.StatusNotFound, bytes: 9, level: "INFO"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set(requestid.Header, "req-5")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry), tt.path)
+		assert.Equal(t, "http request", entry["msg"])
+		assert.Equal(t, tt.level, entry["level"], tt.path)
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, tt.route, entry["route"], tt.path)
+		assert.Equal(t, tt.path, entry["path"])
+		assert.Equal(t, float64(tt.status), entry["status"], tt.path)
+		assert.Equal(t, tt.bytes, entry["bytes"], tt.path)
+		assert.Equal(t, "req-5", entry["request_id"])
+		assert.Contains(t, entry, "latency_ms")
+	}
+}
+
+func TestAccessLog_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	router := gin.New()
+	assert.NoError(t, router.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	router.Use(AccessLog(slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for remote, want := range map[string]string{
+		"10.0.0.1:1234":     "203.0.113.9",
+		"198.51.100.7:1234": "198.51.100.7",
+	} {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = remote
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry), remote)
+		assert.Equal(t, want, entry["client_ip"], remote)
+	}
+}
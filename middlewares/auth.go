@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/problem"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errMissingToken = apperror.Unauthorized("missing_token", "authentication required")
+
+// Authenticate asks each extractor in turn for the caller and stores the
+// first one found in the request context, where handlers read it with
+// auth.FromContext. Requests without credentials, or whose credentials are
+// rejected, are answered with 401 and a WWW-Authenticate challenge for every
+// Authorization scheme the extractors accept. Other errors, such as an
+// oversized signed body, are answered without a challenge.
+func Authenticate(extractors ...auth.IdentityExtractor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, extractor := range extractors {
+			principal, err := extractor.Extract(c.Request)
+			if err != nil {
+				if challenger, ok := extractor.(auth.Challenger); ok && apperror.From(err).Kind == apperror.KindUnauthorized {
+					c.Header("WWW-Authenticate", fmt.Sprintf("%s error=%q", challenger.Scheme(), "invalid_token"))
+				}
+				writeAuthError(c, err)
+				return
+			}
+			if principal == nil {
+				continue
+			}
+
+			trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("enduser.id", strconv.FormatUint(uint64(principal.UserID), 10)))
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+			c.Next()
+			return
+		}
+
+		for _, extractor := range extractors {
+			if challenger, ok := extractor.(auth.Challenger); ok {
+				c.Writer.Header().Add("WWW-Authenticate", challenger.Scheme())
+			}
+		}
+		writeAuthError(c, errMissingToken)
+	}
+}
+
+func writeAuthError(c *gin.Context, err error) {
+	appErr := apperror.From(err)
+	if appErr.Kind == apperror.KindInternal {
+		slog.ErrorContext(c.Request.Context(), "access check failed", "path", c.Request.URL.Path, "error", err)
+	}
+	problem.Write(c, problem.New(apperror.HTTPStatus(appErr.Kind), appErr.Code, appErr.Message))
+}
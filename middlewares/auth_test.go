@@ -0,0 +1,183 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/pkg/signature"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVerifier struct {
+	token string
+}
+
+func (f fakeVerifier) VerifyAccessToken(ctx context.Context, token string) (*auth.Principal, error) {
+	if token != f.token {
+		return nil, apperror.Unauthorized("invalid_token", "access token is invalid or expired")
+	}
+	return &auth.Principal{UserID: 7, Username: "arthur"}, nil
+}
+
+func (f fakeVerifier) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	return f.VerifyAccessToken(ctx, key)
+}
+
+// VerifySignature treats the token as the shared secret of every client.
+func (f fakeVerifier) VerifySignature(ctx context.Context, credentials *signature.Credentials, stringToSign string) (*auth.Principal, error) {
+	if !signature.Verify([]byte(f.token), stringToSign, credentials.Signature) {
+		return nil, apperror.Unauthorized("invalid_signature", "request signature is invalid")
+	}
+	return &auth.Principal{UserID: 7, SigningClientID: 2}, nil
+}
+
+func serveWithAuthorization(header string) (*httptest.ResponseRecorder, *auth.Principal) {
+	gin.SetMode(gin.TestMode)
+	var principal *auth.Principal
+
+	router := gin.New()
+	router.Use(Authenticate(auth.TrustedHeader("X-User-ID", nil), auth.BearerToken(fakeVerifier{token: "good"})))
+	router.GET("/ping", func(c *gin.Context) {
+		principal, _ = auth.FromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	router.ServeHTTP(w, req)
+	return w, principal
+}
+
+func TestAuthenticate_ValidToken(t *testing.T) {
+	for _, header := range []string{"Bearer good", "bearer  good"} {
+		w, principal := serveWithAuthorization(header)
+
+		assert.Equal(t, http.StatusNoContent, w.Code, header)
+		assert.Equal(t, &auth.Principal{UserID: 7, Username: "arthur"}, principal, header)
+	}
+}
+
+func TestAuthenticate_Rejects(t *testing.T) {
+	tests := []struct {
+		header    string
+		code      string
+		challenge string
+	}{
+		{"", "missing_token", "Bearer"},
+		{"Basic YXJ0aHVyOnB3", "missing_token", "Bearer"},
+		{"Bearer", "missing_token", "Bearer"},
+		{"Bearer bad", "invalid_token", `Bearer error="invalid_token"`},
+	}
+
+	for _, tt := range tests {
+		w, principal := serveWithAuthorization(tt.header)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, tt.header)
+		assert.Nil(t, principal, tt.header)
+		assert.Equal(t, tt.challenge, w.Header().Get("WWW-Authenticate"), tt.header)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), tt.header)
+		var body problem.Details
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, tt.code, body.Code, tt.header)
+	}
+}
+
+func TestAuthenticate_FirstExtractorWins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var principal *auth.Principal
+
+	router := gin.New()
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	router.Use(Authenticate(auth.TrustedHeader("X-User-ID", proxies), auth.BearerToken(fakeVerifier{token: "good"})))
+	router.GET("/ping", func(c *gin.Context) {
+		principal, _ = auth.FromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = "10.1.2.3:4567"
+	req.Header.Set("X-User-ID", "42")
+	req.Header.Set("Authorization", "Bearer bad")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, &auth.Principal{UserID: 42}, principal)
+}
+
+func TestAuthenticate_ChallengesEveryScheme(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate(auth.BearerToken(fakeVerifier{token: "good"}), auth.APIKey(fakeVerifier{token: "good"})))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, []string{"Bearer", "ApiKey"}, w.Header().Values("WWW-Authenticate"))
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Authorization", "ApiKey bad")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `ApiKey error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestAuthenticate_SignedRequestKeepsBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var (
+		principal *auth.Principal
+		body      []byte
+	)
+	router := gin.New()
+	router.Use(Authenticate(auth.BearerToken(fakeVerifier{token: "good"}), auth.SignedRequest(fakeVerifier{token: "good"}, 1024)))
+	router.POST("/users", func(c *gin.Context) {
+		principal, _ = auth.FromContext(c.Request.Context())
+		body, _ = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusNoContent)
+	})
+	sign := func(secret, payload string) *http.Request {
+		timestamp, nonce := time.Now(), "0123456789abcdef"
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(payload))
+		req.Header.Set("Authorization", signature.Authorization("sc_client", []byte(secret), timestamp, nonce,
+			signature.StringToSign(req, timestamp, nonce, []byte(payload))))
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, sign("good", `{"username":"arthur"}`))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, &auth.Principal{UserID: 7, SigningClientID: 2}, principal)
+	assert.Equal(t, `{"username":"arthur"}`, string(body))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, sign("bad", `{"username":"arthur"}`))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `HMAC-SHA256 error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, sign("good", strings.Repeat("a", 1025)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Values("WWW-Authenticate"), "an oversized body is not a credential problem")
+	var details problem.Details
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, "body_too_large", details.Code)
+}
@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/pkg/auth"
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubjectLoader looks up what the authenticated caller, and the user they
+// act on, are allowed to do.
+type SubjectLoader interface {
+	Subject(ctx context.Context, principal *auth.Principal) (*authz.Subject, error)
+	UserPermissions(ctx context.Context, userID uint) ([]authz.Permission, error)
+}
+
+// Authorize returns a middleware factory: the handler it builds for an
+// action lets the request through only if policy allows the caller, loaded
+// on every request so that role changes apply at once, to perform that
+// action on the user named by the ":id" path parameter, whose permissions are
+// loaded too when they are someone else. It must run after
+// Authenticate; denied requests are answered with 403.
+func Authorize(policy authz.Policy, loader SubjectLoader) func(action authz.Action) gin.HandlerFunc {
+	return func(action authz.Action) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			principal, ok := auth.FromContext(c.Request.Context())
+			if !ok {
+				writeAuthError(c, errMissingToken)
+				return
+			}
+			subject, err := loader.Subject(c.Request.Context(), principal)
+			if err != nil {
+				writeAuthError(c, err)
+				return
+			}
+
+			var resource authz.Resource
+			// An ID the controller will reject as malformed targets nobody.
+			if id, err := strconv.ParseUint(c.Param("id"), 10, 0); err == nil {
+				resource.UserID = uint(id)
+			}
+			if resource.UserID != 0 && resource.UserID != subject.UserID {
+				resource.Permissions, err = loader.UserPermissions(c.Request.Context(), resource.UserID)
+				if err != nil {
+					writeAuthError(c, err)
+					return
+				}
+			}
+			if err := policy.Authorize(subject, action, resource); err != nil {
+				writeAuthError(c, err)
+				return
+			}
+			c.Next()
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSubjectLoader map[uint][]authz.Permission
+
+func (f fakeSubjectLoader) Subject(ctx context.Context, principal *auth.Principal) (*authz.Subject, error) {
+	permissions, ok := f[principal.UserID]
+	if !ok {
+		return nil, errors.New("db error")
+	}
+	return &authz.Subject{UserID: principal.UserID, Permissions: permissions}, nil
+}
+
+func (f fakeSubjectLoader) UserPermissions(ctx context.Context, userID uint) ([]authz.Permission, error) {
+	if userID == 12 {
+		return nil, errors.New("db error")
+	}
+	return f[userID], nil
+}
+
+func serveAuthorized(principal *auth.Principal, path string) *httptest.ResponseRecorder {
+	return serveAuthorizedRequest(principal, http.MethodGet, path)
+}
+
+func serveAuthorizedRequest(principal *auth.Principal, method, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	authorize := Authorize(authz.DefaultPolicy(), fakeSubjectLoader{
+		7:  nil,
+		8:  {authz.PermUsersReadAll},
+		10: {authz.PermUsersReadAll, authz.PermUsersUpdate},
+		11: authz.Permissions,
+	})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+		}
+	})
+	ok := func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	}
+	router.GET("/users/:id", authorize(authz.ActionReadUser), ok)
+	router.PATCH("/users/:id", authorize(authz.ActionUpdateUser), ok)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		path      string
+		status    int
+		code      string
+	}{
+		{"self", &auth.Principal{UserID: 7}, "/users/7", http.StatusNoContent, ""},
+		{"other user", &auth.Principal{UserID: 7}, "/users/8", http.StatusForbidden, "forbidden"},
+		{"malformed id", &auth.Principal{UserID: 7}, "/users/seven", http.StatusForbidden, "forbidden"},
+		{"permission", &auth.Principal{UserID: 8}, "/users/7", http.StatusNoContent, ""},
+		{"unauthenticated", nil, "/users/7", http.StatusUnauthorized, "missing_token"},
+		{"loader error", &auth.Principal{UserID: 9}, "/users/9", http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		w := serveAuthorized(tt.principal, tt.path)
+
+		assert.Equal(t, tt.status, w.Code, tt.name)
+		if tt.code == "" {
+			continue
+		}
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"), tt.name)
+		var body problem.Details
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, tt.code, body.Code, tt.name)
+	}
+}
+
+func TestAuthorize_TargetPermissions(t *testing.T) {
+	operator := &auth.Principal{UserID: 10}
+
+	assert.Equal(t, http.StatusNoContent, serveAuthorizedRequest(operator, http.MethodPatch, "/users/8").Code)
+	assert.Equal(t, http.StatusForbidden, serveAuthorizedRequest(operator, http.MethodPatch, "/users/11").Code, "an operator cannot take over an admin")
+	assert.Equal(t, http.StatusNoContent, serveAuthorizedRequest(&auth.Principal{UserID: 11}, http.MethodPatch, "/users/11").Code)
+	assert.Equal(t, http.StatusInternalServerError, serveAuthorizedRequest(operator, http.MethodPatch, "/users/12").Code)
+}
@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+)
+
+type PanicReport struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Value     string    `json:"value"`
+	Stack     string    `json:"stack"`
+}
+
+type ErrorReporter interface {
+	Report(ctx context.Context, report PanicReport) error
+}
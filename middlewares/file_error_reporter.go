@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileErrorReporter appends panic reports to a file it keeps open until
+// Close.
+type FileErrorReporter interface {
+	ErrorReporter
+	io.Closer
+}
+
+type fileErrorReporter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileErrorReporter(path string) (FileErrorReporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileErrorReporter{file: file}, nil
+}
+
+func (r *fileErrorReporter) Report(ctx context.Context, report PanicReport) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file; reports made afterwards fail.
+func (r *fileErrorReporter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Learn_Jenkins/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clientmodel "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics(), HandlePanic(nil))
+	router.GET("/metrics-test/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/metrics-test-panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		path   string
+		route  string
+		status string
+	}{
+		{path: "/metrics-test/1", route: "/metrics-test/:id", status: "204"},
+		{path: "/metrics-test/2", route: "/metrics-test/:id", status: "204"},
+		{path: "/metrics-test-panic", route: "/metrics-test-panic", status: "500"},
+		{path: "/metrics-test-missing", route: unmatchedRoute, status: "404"},
+	}
+	for _, tt := range tests {
+		counter := metrics.HTTPRequests.WithLabelValues(http.MethodGet, tt.route, tt.status)
+		histogram := metrics.HTTPRequestDuration.WithLabelValues(http.MethodGet, tt.route, tt.status)
+		before, beforeSamples := testutil.ToFloat64(counter), sampleCount(t, histogram)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		assert.Equal(t, before+1, testutil.ToFloat64(counter), tt.path)
+		assert.Equal(t, beforeSamples+1, sampleCount(t, histogram), tt.path)
+	}
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	var m clientmodel.Metric
+	if err := observer.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("failed to read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
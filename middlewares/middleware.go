@@ -1,18 +1,55 @@
 package middlewares
 
 import (
+	"Learn_Jenkins/pkg/metrics"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/pkg/requestid"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func HandlePanic() gin.HandlerFunc {
+func HandlePanic(reporter ErrorReporter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
-			if r := recover(); r != nil {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Internal server error"})
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+
+			metrics.PanicsRecovered.Inc()
+			report := PanicReport{
+				Time:      time.Now().UTC(),
+				RequestID: requestid.FromContext(c.Request.Context()),
+				Method:    c.Request.Method,
+				Path:      c.Request.URL.Path,
+				Value:     fmt.Sprint(r),
+				Stack:     string(debug.Stack()),
+			}
+			slog.ErrorContext(c.Request.Context(), "panic recovered",
+				"method", report.Method,
+				"path", report.Path,
+				"panic", report.Value,
+				"stack", report.Stack,
+			)
+			if reporter != nil {
+				if err := reporter.Report(c.Request.Context(), report); err != nil {
+					slog.ErrorContext(c.Request.Context(), "failed to report panic", "error", err)
+				}
+			}
+
+			if c.Writer.Written() {
 				c.Abort()
+				return
 			}
+			problem.Write(c, problem.New(http.StatusInternalServerError, "internal_error", "Internal server error"))
 		}()
 		c.Next()
 	}
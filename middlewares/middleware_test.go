@@ -0,0 +1,121 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"Learn_Jenkins/pkg/metrics"
+	"Learn_Jenkins/pkg/problem"
+	"Learn_Jenkins/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReporter struct {
+	reports []PanicReport
+}
+
+func (f *fakeReporter) Report(ctx context.Context, report PanicReport) error {
+	f.reports = append(f.reports, report)
+	return nil
+}
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(requestid.NewLogHandler(slog.NewJSONHandler(&buf, nil))))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestHandlePanic_Returns500AndReports(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := captureLogs(t)
+	reporter := &fakeReporter{}
+	before := testutil.ToFloat64(metrics.PanicsRecovered)
+
+	router := gin.New()
+	router.Use(RequestID(), HandlePanic(reporter))
+	router.GET("/boom", func(c *gin.Context) {
+		panic("kaboom")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set(requestid.Header, "req-42")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+	var resp problem.Details
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "internal_error", resp.Code)
+	assert.Equal(t, "req-42", resp.RequestID)
+	assert.NotContains(t, w.Body.String(), "kaboom")
+
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.PanicsRecovered))
+	assert.Len(t, reporter.reports, 1)
+	assert.Equal(t, "kaboom", reporter.reports[0].Value)
+	assert.Equal(t, "req-42", reporter.reports[0].RequestID)
+	assert.Equal(t, "/boom", reporter.reports[0].Path)
+	assert.Contains(t, reporter.reports[0].Stack, "middleware_test.go")
+
+	var entry map[string]interface{}
+	err = json.Unmarshal(logs.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "panic recovered", entry["msg"])
+	assert.Equal(t, "kaboom", entry["panic"])
+	assert.Equal(t, "req-42", entry["request_id"])
+	assert.NotEmpty(t, entry["stack"])
+}
+
+func TestHandlePanic_WithoutReporter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	captureLogs(t)
+
+	router := gin.New()
+	router.Use(HandlePanic(nil))
+	router.GET("/boom", func(c *gin.Context) {
+		panic(assert.AnError)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestFileErrorReporter_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "panics.log")
+	reporter, err := NewFileErrorReporter(path)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, reporter.Report(ctx, PanicReport{Value: "first", Path: "/a"}))
+	assert.NoError(t, reporter.Report(ctx, PanicReport{Value: "second", Path: "/b"}))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var report PanicReport
+	assert.NoError(t, json.Unmarshal(lines[1], &report))
+	assert.Equal(t, "second", report.Value)
+	assert.Equal(t, "/b", report.Path)
+
+	assert.NoError(t, reporter.Close())
+	assert.Error(t, reporter.Report(ctx, PanicReport{Value: "third", Path: "/c"}))
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"Learn_Jenkins/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID accepts a well-formed X-Request-ID from the client or generates
+// one, then exposes it on the request context, the gin context and the
+// response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Set(requestid.LogKey, id)
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
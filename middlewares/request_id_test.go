@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Learn_Jenkins/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithRequestID(header string) (*httptest.ResponseRecorder, string, string) {
+	gin.SetMode(gin.TestMode)
+	var fromContext, fromKeys string
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/ping", func(c *gin.Context) {
+		fromContext = requestid.FromContext(c.Request.Context())
+		fromKeys = c.GetString(requestid.LogKey)
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	if header != "" {
+		req.Header.Set(requestid.Header, header)
+	}
+	router.ServeHTTP(w, req)
+	return w, fromContext, fromKeys
+}
+
+func TestRequestID_AcceptsClientID(t *testing.T) {
+	w, fromContext, fromKeys := serveWithRequestID("req-42")
+
+	assert.Equal(t, "req-42", w.Header().Get(requestid.Header))
+	assert.Equal(t, "req-42", fromContext)
+	assert.Equal(t, "req-42", fromKeys)
+}
+
+func TestRequestID_GeneratesWhenMissingOrInvalid(t *testing.T) {
+	for _, header := range []string{"", "bad id */ DROP TABLE users", strings.Repeat("x", 200)} {
+		w, fromContext, _ := serveWithRequestID(header)
+
+		id := w.Header().Get(requestid.Header)
+		assert.True(t, requestid.Valid(id), header)
+		assert.NotEqual(t, header, id)
+		assert.Equal(t, id, fromContext)
+	}
+}
@@ -0,0 +1,246 @@
+package migrations
+
+import (
+	"Learn_Jenkins/config"
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(files, "postgres")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_users_table", migrations[0].Name)
+	assert.Contains(t, migrations[0].Up, "CREATE TABLE")
+	assert.Contains(t, migrations[0].Down, "DROP TABLE")
+}
+
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/000010_add_email.up.sql":      {Data: []byte("up 10")},
+		"sql/000010_add_email.down.sql":    {Data: []byte("down 10")},
+		"sql/000002_create_users.up.sql":   {Data: []byte("up 2")},
+		"sql/000002_create_users.down.sql": {Data: []byte("down 2")},
+		"sql/README.md":                    {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys, "sql")
+
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(2), migrations[0].Version)
+	assert.Equal(t, "up 2", migrations[0].Up)
+	assert.Equal(t, int64(10), migrations[1].Version)
+	assert.Equal(t, "down 10", migrations[1].Down)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"sql/000001_create_users.up.sql": {Data: []byte("up")},
+		},
+		"bad version": {
+			"sql/abc_create_users.up.sql":   {Data: []byte("up")},
+			"sql/abc_create_users.down.sql": {Data: []byte("down")},
+		},
+		"bad direction": {
+			"sql/000001_create_users.sideways.sql": {Data: []byte("up")},
+		},
+		"conflicting names": {
+			"sql/000001_create_users.up.sql":    {Data: []byte("up")},
+			"sql/000001_create_people.down.sql": {Data: []byte("down")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(fsys, "sql")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, *sql.DB) {
+	db, err := config.InitTestDatabase(config.DatabaseConfig{Driver: config.DriverSQLite})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	migrator, err := New(sqlDB, SQLite)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	return migrator, sqlDB
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	var count int
+	err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = $1", name).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to inspect schema: %v", err)
+	}
+	return count > 0
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+
+	pending, err := migrator.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, pending, len(migrator.migrations))
+
+	applied, err := migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, applied, len(migrator.migrations))
+	assert.True(t, tableExists(t, db, "users"))
+
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+		assert.NotNil(t, status.AppliedAt)
+	}
+
+	reverted, err := migrator.Down(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, migrator.Latest(), reverted.Version)
+
+	pending, err = migrator.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestMigrator_Redo(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+
+	_, err := migrator.Redo(ctx)
+	assert.ErrorIs(t, err, ErrNoMigrationApplied)
+
+	_, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	before := appliedAt(t, db)
+
+	redone, err := migrator.Redo(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, migrator.Latest(), redone.Version)
+	pending, err := migrator.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// Only the latest migration was reverted and recorded again.
+	after := appliedAt(t, db)
+	assert.Len(t, after, len(migrator.migrations))
+	for version, at := range before {
+		if version != migrator.Latest() {
+			assert.Equal(t, at, after[version], "version %d", version)
+		}
+	}
+}
+
+// appliedAt returns the applied_at column of schema_migrations by version,
+// as stored.
+func appliedAt(t *testing.T, db *sql.DB) map[int64]string {
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		t.Fatalf("failed to read schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	versions := map[int64]string{}
+	for rows.Next() {
+		var (
+			version int64
+			at      string
+		)
+		if err := rows.Scan(&version, &at); err != nil {
+			t.Fatalf("failed to read schema_migrations: %v", err)
+		}
+		versions[version] = at
+	}
+	return versions
+}
+
+func TestMigrator_StatusDoesNotCreateTable(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+
+	pending, err := migrator.Pending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, pending, len(migrator.migrations))
+	assert.False(t, tableExists(t, db, "schema_migrations"))
+}
+
+func TestMigrator_RedoLeavesOtherPendingMigrations(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	ctx := context.Background()
+	latest := migrator.Latest()
+	migrator.migrations = append(migrator.migrations, Migration{
+		Version: latest + 1,
+		Name:    "create_redo_probe",
+		Up:      "CREATE TABLE redo_probe (id INTEGER);",
+		Down:    "DROP TABLE redo_probe;",
+	})
+	_, err := migrator.Up(ctx)
+	assert.NoError(t, err)
+	migrator.migrations = append(migrator.migrations, Migration{
+		Version: latest + 2,
+		Name:    "create_later",
+		Up:      "CREATE TABLE later (id INTEGER);",
+		Down:    "DROP TABLE later;",
+	})
+
+	redone, err := migrator.Redo(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, latest+1, redone.Version)
+	assert.True(t, tableExists(t, db, "redo_probe"))
+	assert.False(t, tableExists(t, db, "later"))
+	pending, err := migrator.Pending(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, latest+2, pending[0].Version)
+	}
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	migrator.migrations = append(migrator.migrations, Migration{
+		Version: migrator.Latest() + 1,
+		Name:    "broken",
+		Up:      "CREATE TABLE broken (id INTEGER); INSERT INTO missing VALUES (1);",
+		Down:    "DROP TABLE broken;",
+	})
+
+	_, err := migrator.Up(context.Background())
+
+	assert.ErrorContains(t, err, "broken failed")
+	assert.False(t, tableExists(t, db, "broken"))
+	pending, err := migrator.Pending(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestNew_UnsupportedDialect(t *testing.T) {
+	_, err := New(nil, "mysql")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    username TEXT NOT NULL,
+    CONSTRAINT uni_users_username UNIQUE (username)
+);
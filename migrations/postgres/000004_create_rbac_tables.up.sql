@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    CONSTRAINT uni_roles_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    CONSTRAINT uni_permissions_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id BIGINT NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id BIGINT NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full access, including creating users and managing roles'),
+    ('operator', 'Reads and updates any user'),
+    ('viewer', 'Reads any user');
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:create', 'Create users'),
+    ('users:read_all', 'Read and list any user'),
+    ('users:update', 'Update any user'),
+    ('users:delete', 'Delete users'),
+    ('roles:manage', 'List roles and assign or revoke them');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles, permissions
+WHERE roles.name = 'admin'
+   OR (roles.name = 'operator' AND permissions.name IN ('users:read_all', 'users:update'))
+   OR (roles.name = 'viewer' AND permissions.name = 'users:read_all');
@@ -0,0 +1,2 @@
+DELETE FROM permissions WHERE name = 'api_keys:manage';
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE name = 'signing_clients:manage';
+DROP TABLE IF EXISTS signature_nonces;
+DROP TABLE IF EXISTS signing_clients;
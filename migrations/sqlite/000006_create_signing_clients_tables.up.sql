@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS signing_clients (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT uni_signing_clients_client_id UNIQUE (client_id)
+);
+CREATE INDEX IF NOT EXISTS idx_signing_clients_user_id ON signing_clients (user_id);
+
+CREATE TABLE IF NOT EXISTS signature_nonces (
+    client_id TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (client_id, nonce)
+);
+CREATE INDEX IF NOT EXISTS idx_signature_nonces_expires_at ON signature_nonces (expires_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('signing_clients:manage', 'List, create and revoke the request signing clients of any user');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id FROM roles, permissions
+WHERE roles.name = 'admin' AND permissions.name = 'signing_clients:manage';
@@ -0,0 +1,58 @@
+// Package apikey generates and parses API keys. A key looks like
+// "lj_<prefix>_<secret>": the prefix is stored in clear to find the key, the
+// secret only as part of the SHA-256 of the whole key.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const keyPrefix = "lj_"
+
+// Generate returns a new key and its prefix.
+func Generate() (key, prefix string, err error) {
+	var id [8]byte
+	var secret [32]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(secret[:]); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(id[:])
+	return keyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret[:]), prefix, nil
+}
+
+// Prefix returns the lookup prefix of key, or false if key is not shaped
+// like one this package generates.
+func Prefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, keyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 16 || secret == "" {
+		return "", false
+	}
+	if _, err := hex.DecodeString(prefix); err != nil {
+		return "", false
+	}
+	return prefix, true
+}
+
+// Hash returns the hex SHA-256 of key, which is what gets stored. Keys are
+// random, so a fast hash is enough.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports, in constant time, whether key hashes to hash.
+func Matches(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
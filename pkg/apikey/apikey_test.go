@@ -0,0 +1,39 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, prefix, err := Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, "lj_"+prefix+"_"), key)
+	got, ok := Prefix(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, got)
+	assert.True(t, Matches(key, Hash(key)))
+
+	other, _, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.False(t, Matches(other, Hash(key)))
+}
+
+func TestPrefix_Malformed(t *testing.T) {
+	for _, key := range []string{
+		"",
+		"0123456789abcdef_secret",
+		"lj_0123456789abcdef",
+		"lj_0123456789abcdef_",
+		"lj_0123456789abcde_secret",
+		"lj_0123456789abcdeg_secret",
+	} {
+		_, ok := Prefix(key)
+		assert.False(t, ok, key)
+	}
+}
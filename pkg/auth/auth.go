@@ -0,0 +1,38 @@
+// Package auth carries the authenticated caller through context.Context.
+package auth
+
+import "context"
+
+// Principal is the caller a request was authenticated as. Username is only
+// set for access tokens. APIKeyID is set when the caller used an API key and
+// SigningClientID for signed requests; for both, Scopes lists the only
+// permissions the request may use.
+type Principal struct {
+	UserID          uint
+	Username        string
+	APIKeyID        uint
+	Scopes          []string
+	SigningClientID uint
+}
+
+// Delegated reports whether the caller used a machine credential rather than
+// their own login.
+func (p *Principal) Delegated() bool {
+	return p.APIKeyID != 0 || p.SigningClientID != 0
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the caller stored by the authentication middleware, or
+// false for unauthenticated requests.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	principal, ok := ctx.Value(contextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
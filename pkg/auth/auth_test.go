@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := &Principal{UserID: 7, Username: "arthur"}
+	got, ok := FromContext(NewContext(context.Background(), principal))
+	assert.True(t, ok)
+	assert.Same(t, principal, got)
+
+	_, ok = FromContext(NewContext(context.Background(), nil))
+	assert.False(t, ok)
+}
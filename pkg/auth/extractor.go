@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/pkg/signature"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidIdentity    = apperror.Unauthorized("invalid_identity", "identity header is not a valid user ID")
+	ErrMalformedSignature = apperror.Unauthorized("invalid_signature", "signature credentials are malformed")
+	ErrSignedBodyTooLarge = apperror.BadRequest("body_too_large", "signed request body is too large")
+)
+
+// IdentityExtractor finds the caller of a request. It returns nil, nil when
+// the request carries no credentials it understands, so that the next
+// extractor in the chain can try.
+type IdentityExtractor interface {
+	Extract(r *http.Request) (*Principal, error)
+}
+
+// Challenger is implemented by extractors reading an Authorization scheme.
+// Its name is advertised in the WWW-Authenticate header of 401 responses.
+type Challenger interface {
+	Scheme() string
+}
+
+// TokenVerifier resolves a bearer access token to the caller it was issued to.
+type TokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, token string) (*Principal, error)
+}
+
+type bearerToken struct {
+	verifier TokenVerifier
+}
+
+// BearerToken reads "Authorization: Bearer <token>" and checks the token
+// with verifier.
+func BearerToken(verifier TokenVerifier) IdentityExtractor {
+	return bearerToken{verifier: verifier}
+}
+
+func (b bearerToken) Scheme() string {
+	return "Bearer"
+}
+
+func (b bearerToken) Extract(r *http.Request) (*Principal, error) {
+	credentials, ok := Credentials(r, b.Scheme())
+	if !ok {
+		return nil, nil
+	}
+	return b.verifier.VerifyAccessToken(r.Context(), credentials)
+}
+
+// Credentials returns what follows scheme, matched case-insensitively, in
+// the Authorization header.
+func Credentials(r *http.Request, scheme string) (string, bool) {
+	got, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	credentials = strings.TrimSpace(credentials)
+	if !strings.EqualFold(got, scheme) || credentials == "" {
+		return "", false
+	}
+	return credentials, true
+}
+
+// APIKeyVerifier resolves an API key to the caller that owns it.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+type apiKey struct {
+	verifier APIKeyVerifier
+}
+
+// APIKey reads "Authorization: ApiKey <key>" and checks the key with
+// verifier.
+func APIKey(verifier APIKeyVerifier) IdentityExtractor {
+	return apiKey{verifier: verifier}
+}
+
+func (a apiKey) Scheme() string {
+	return "ApiKey"
+}
+
+func (a apiKey) Extract(r *http.Request) (*Principal, error) {
+	credentials, ok := Credentials(r, a.Scheme())
+	if !ok {
+		return nil, nil
+	}
+	return a.verifier.VerifyAPIKey(r.Context(), credentials)
+}
+
+// SignatureVerifier resolves signed request credentials to the caller that
+// owns the signing client, given the string the client should have signed.
+type SignatureVerifier interface {
+	VerifySignature(ctx context.Context, credentials *signature.Credentials, stringToSign string) (*Principal, error)
+}
+
+type signedRequest struct {
+	verifier SignatureVerifier
+	maxBody  int64
+}
+
+// SignedRequest reads "Authorization: HMAC-SHA256 client=..., timestamp=...,
+// nonce=..., signature=..." and checks the signature with verifier. Since the
+// body is part of what is signed, it is read up front, up to maxBody bytes,
+// and put back for the handler.
+func SignedRequest(verifier SignatureVerifier, maxBody int64) IdentityExtractor {
+	return signedRequest{verifier: verifier, maxBody: maxBody}
+}
+
+func (s signedRequest) Scheme() string {
+	return signature.Scheme
+}
+
+func (s signedRequest) Extract(r *http.Request) (*Principal, error) {
+	value, ok := Credentials(r, s.Scheme())
+	if !ok {
+		return nil, nil
+	}
+	credentials, err := signature.ParseCredentials(value)
+	if err != nil {
+		return nil, ErrMalformedSignature
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(r.Body, s.maxBody+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(body)) > s.maxBody {
+			return nil, ErrSignedBodyTooLarge
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return s.verifier.VerifySignature(r.Context(), credentials, signature.StringToSign(r, credentials.Timestamp, credentials.Nonce, body))
+}
+
+type trustedHeader struct {
+	name    string
+	proxies []netip.Prefix
+}
+
+// TrustedHeader reads the numeric user ID an authenticating proxy puts in
+// header name. The header is only honored when the request comes straight
+// from one of proxies; from anywhere else it is ignored, since any client
+// could set it. The principal it returns has no username.
+func TrustedHeader(name string, proxies []netip.Prefix) IdentityExtractor {
+	return trustedHeader{name: name, proxies: proxies}
+}
+
+func (h trustedHeader) Extract(r *http.Request) (*Principal, error) {
+	value := r.Header.Get(h.name)
+	if value == "" || !h.fromProxy(r.RemoteAddr) {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || id == 0 {
+		return nil, ErrInvalidIdentity
+	}
+	return &Principal{UserID: uint(id)}, nil
+}
+
+func (h trustedHeader) fromProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, proxy := range h.proxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
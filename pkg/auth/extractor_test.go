@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"Learn_Jenkins/pkg/signature"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBadToken = errors.New("bad token")
+
+type fakeVerifier struct{}
+
+func (fakeVerifier) VerifyAccessToken(ctx context.Context, token string) (*Principal, error) {
+	if token != "good" {
+		return nil, errBadToken
+	}
+	return &Principal{UserID: 7, Username: "arthur"}, nil
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *Principal
+		err    error
+	}{
+		{"", nil, nil},
+		{"Basic YXJ0aHVyOnB3", nil, nil},
+		{"Bearer ", nil, nil},
+		{"bearer good", &Principal{UserID: 7, Username: "arthur"}, nil},
+		{"Bearer bad", nil, errBadToken},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", tt.header)
+
+		principal, err := BearerToken(fakeVerifier{}).Extract(req)
+
+		assert.Equal(t, tt.want, principal, tt.header)
+		assert.ErrorIs(t, err, tt.err, tt.header)
+	}
+}
+
+func (fakeVerifier) VerifyAPIKey(ctx context.Context, key string) (*Principal, error) {
+	if key != "lj_good" {
+		return nil, errBadToken
+	}
+	return &Principal{UserID: 7, APIKeyID: 3, Scopes: []string{}}, nil
+}
+
+func TestAPIKey(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *Principal
+		err    error
+	}{
+		{"", nil, nil},
+		{"Bearer lj_good", nil, nil},
+		{"apikey lj_good", &Principal{UserID: 7, APIKeyID: 3, Scopes: []string{}}, nil},
+		{"ApiKey lj_bad", nil, errBadToken},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", tt.header)
+
+		principal, err := APIKey(fakeVerifier{}).Extract(req)
+
+		assert.Equal(t, tt.want, principal, tt.header)
+		assert.ErrorIs(t, err, tt.err, tt.header)
+	}
+}
+
+var signingSecret = []byte("shared-secret")
+
+func (fakeVerifier) VerifySignature(ctx context.Context, credentials *signature.Credentials, stringToSign string) (*Principal, error) {
+	if credentials.ClientID != "sc_good" || !signature.Verify(signingSecret, stringToSign, credentials.Signature) {
+		return nil, errBadToken
+	}
+	return &Principal{UserID: 7, SigningClientID: 5}, nil
+}
+
+func newSignedRequest(t *testing.T, clientID, body string) *http.Request {
+	t.Helper()
+	timestamp := time.Unix(1735732800, 0)
+	nonce := "0123456789abcdef"
+	req := httptest.NewRequest(http.MethodPost, "/users?limit=5", strings.NewReader(body))
+	req.Header.Set("Authorization", signature.Authorization(clientID, signingSecret, timestamp, nonce,
+		signature.StringToSign(req, timestamp, nonce, []byte(body))))
+	return req
+}
+
+func TestSignedRequest(t *testing.T) {
+	extractor := SignedRequest(fakeVerifier{}, 64)
+
+	t.Run("valid signature keeps the body", func(t *testing.T) {
+		req := newSignedRequest(t, "sc_good", `{"username":"arthur"}`)
+
+		principal, err := extractor.Extract(req)
+
+		require.NoError(t, err)
+		assert.Equal(t, &Principal{UserID: 7, SigningClientID: 5}, principal)
+		body, _ := io.ReadAll(req.Body)
+		assert.Equal(t, `{"username":"arthur"}`, string(body))
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		req := newSignedRequest(t, "sc_good", `{"username":"arthur"}`)
+		req.Body = io.NopCloser(strings.NewReader(`{"username":"zaphod"}`))
+
+		_, err := extractor.Extract(req)
+
+		assert.ErrorIs(t, err, errBadToken)
+	})
+
+	t.Run("other scheme", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer good")
+
+		principal, err := extractor.Extract(req)
+
+		assert.NoError(t, err)
+		assert.Nil(t, principal)
+	})
+
+	t.Run("malformed credentials", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "HMAC-SHA256 client=sc_good")
+
+		_, err := extractor.Extract(req)
+
+		assert.ErrorIs(t, err, ErrMalformedSignature)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		req := newSignedRequest(t, "sc_good", strings.Repeat("x", 65))
+
+		_, err := extractor.Extract(req)
+
+		assert.ErrorIs(t, err, ErrSignedBodyTooLarge)
+	})
+}
+
+func TestTrustedHeader(t *testing.T) {
+	extractor := TrustedHeader("X-User-ID", []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::1/128"),
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		value      string
+		want       *Principal
+		err        error
+	}{
+		{"trusted proxy", "10.1.2.3:4567", "42", &Principal{UserID: 42}, nil},
+		{"trusted IPv6 proxy", "[::1]:4567", "42", &Principal{UserID: 42}, nil},
+		{"untrusted peer", "192.168.1.1:4567", "42", nil, nil},
+		{"no header", "10.1.2.3:4567", "", nil, nil},
+		{"not a number", "10.1.2.3:4567", "arthur", nil, ErrInvalidIdentity},
+		{"zero", "10.1.2.3:4567", "0", nil, ErrInvalidIdentity},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.Header.Set("X-User-ID", tt.value)
+
+		principal, err := extractor.Extract(req)
+
+		assert.Equal(t, tt.want, principal, tt.name)
+		assert.ErrorIs(t, err, tt.err, tt.name)
+	}
+}
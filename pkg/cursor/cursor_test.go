@@ -0,0 +1,39 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type payload struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+
+	token, err := codec.Encode(payload{ID: 7, Username: "Arthur"})
+	assert.NoError(t, err)
+
+	var decoded payload
+	err = codec.Decode(token, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, payload{ID: 7, Username: "Arthur"}, decoded)
+}
+
+func TestCodec_RejectsTamperedToken(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+
+	token, err := codec.Encode(payload{ID: 7})
+	assert.NoError(t, err)
+
+	forged, err := NewCodec([]byte("other")).Encode(payload{ID: 8})
+	assert.NoError(t, err)
+
+	var decoded payload
+	assert.ErrorIs(t, codec.Decode(forged, &decoded), ErrInvalidCursor)
+	assert.ErrorIs(t, codec.Decode(token[:len(token)-2], &decoded), ErrInvalidCursor)
+	assert.ErrorIs(t, codec.Decode("not-a-cursor", &decoded), ErrInvalidCursor)
+}
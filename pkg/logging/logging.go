@@ -0,0 +1,46 @@
+// Package logging builds the application's slog logger and bridges other
+// libraries' loggers into it.
+package logging
+
+import (
+	"Learn_Jenkins/pkg/requestid"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New returns a logger writing format ("json" or "text") at level ("debug",
+// "info", "warn" or "error") to w. Every line logged with a request context
+// carries its request_id.
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected %q or %q", format, FormatJSON, FormatText)
+	}
+	return slog.New(requestid.NewLogHandler(handler)), nil
+}
+
+func ParseLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", level)
+	}
+	return lvl, nil
+}
@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"Learn_Jenkins/pkg/requestid"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "warn")
+	assert.NoError(t, err)
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	logger.InfoContext(ctx, "hidden")
+	logger.WarnContext(ctx, "shown", "key", "value")
+
+	entries := decodeLines(t, &buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "shown", entries[0]["msg"])
+	assert.Equal(t, "WARN", entries[0]["level"])
+	assert.Equal(t, "value", entries[0]["key"])
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "TEXT", "DEBUG")
+	assert.NoError(t, err)
+
+	logger.Debug("hello", "key", "value")
+
+	assert.Contains(t, buf.String(), "level=DEBUG msg=hello key=value")
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "xml", "info")
+	assert.ErrorContains(t, err, `unknown log format "xml"`)
+
+	_, err = New(&bytes.Buffer{}, "json", "loud")
+	assert.ErrorContains(t, err, `unknown log level "loud"`)
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		elapsed time.Duration
+		err     error
+		want    string
+		wantErr string
+	}{
+		{name: "fast query at debug", level: "debug", want: "query"},
+		{name: "fast query hidden at info", level: "info"},
+		{name: "slow query", level: "info", elapsed: time.Second, want: "slow query"},
+		{name: "failed query", level: "info", err: errors.New("boom"), want: "query failed", wantErr: "boom"},
+		{name: "record not found is not an error", level: "info", err: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(&buf, "json", tt.level)
+			assert.NoError(t, err)
+			gormLog := NewGormLogger(logger, 200*time.Millisecond)
+
+			ctx := requestid.NewContext(context.Background(), "req-9")
+			gormLog.Trace(ctx, time.Now().Add(-tt.elapsed), func() (string, int64) {
+				return "SELECT 1", 1
+			}, tt.err)
+
+			entries := decodeLines(t, &buf)
+			if tt.want == "" {
+				assert.Empty(t, entries)
+				return
+			}
+			assert.Len(t, entries, 1)
+			assert.Equal(t, tt.want, entries[0]["msg"])
+			assert.Equal(t, "SELECT 1", entries[0]["sql"])
+			assert.Equal(t, float64(1), entries[0]["rows"])
+			assert.Equal(t, "req-9", entries[0]["request_id"])
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, entries[0]["error"])
+			}
+		})
+	}
+}
+
+func TestGormLogger_Silent(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, "json", "debug")
+	gormLog := NewGormLogger(logger, time.Millisecond).LogMode(gormlogger.Silent)
+
+	gormLog.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT 1", 1
+	}, errors.New("boom"))
+	gormLog.Error(context.Background(), "failed %d", 1)
+
+	assert.Empty(t, buf.String())
+}
+
+type secret string
+
+func (secret) LogValue() slog.Value {
+	return slog.StringValue("[REDACTED]")
+}
+
+func TestGormLogger_ParamsFilter(t *testing.T) {
+	logger, _ := New(&bytes.Buffer{}, "json", "debug")
+	filter := NewGormLogger(logger, 0).(gorm.ParamsFilter)
+
+	sql, params := filter.ParamsFilter(context.Background(), "INSERT INTO users VALUES (?, ?, ?)", "arthur", secret("hash"), 7)
+
+	assert.Equal(t, "INSERT INTO users VALUES (?, ?, ?)", sql)
+	assert.Equal(t, []interface{}{"arthur", "[REDACTED]", 7}, params)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, RegisterDBStats(db, "metrics_test"))
+	assert.Error(t, RegisterDBStats(db, "metrics_test"), "registering the same pool twice must fail")
+
+	HTTPRequests.WithLabelValues(http.MethodGet, "/users/:id", "200").Inc()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	body := w.Body.String()
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/users/:id",status="200"}`,
+		`go_sql_open_connections{db_name="metrics_test"}`,
+		`go_sql_idle_connections{db_name="metrics_test"}`,
+		`go_sql_wait_count_total{db_name="metrics_test"}`,
+		`go_sql_wait_duration_seconds_total{db_name="metrics_test"}`,
+		"go_goroutines",
+		"panics_recovered_total",
+	} {
+		assert.Contains(t, body, want)
+	}
+}
@@ -0,0 +1,186 @@
+// Package password hashes and verifies user passwords with Argon2id or
+// bcrypt. Hashes are self-describing, so a Hasher verifies hashes produced
+// with any supported algorithm or parameters and reports when one should be
+// replaced with a hash using the current settings.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// MaxLength is the longest password accepted, in bytes. bcrypt ignores
+// everything past 72 bytes, so the limit applies to both algorithms to keep
+// switching between them safe.
+const MaxLength = 72
+
+var ErrMalformedHash = errors.New("malformed password hash")
+
+type Params struct {
+	Algorithm string
+	// Argon2Memory is in KiB.
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+// DefaultParams follows the OWASP recommendations at the time of writing.
+func DefaultParams() Params {
+	return Params{
+		Algorithm:         AlgorithmArgon2id,
+		Argon2Memory:      64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 2,
+		BcryptCost:        12,
+	}
+}
+
+type Hasher struct {
+	params Params
+
+	dummyOnce sync.Once
+	dummy     string
+}
+
+func NewHasher(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case AlgorithmArgon2id:
+		if params.Argon2Iterations < 1 || params.Argon2Parallelism < 1 {
+			return nil, errors.New("argon2id iterations and parallelism must be at least 1")
+		}
+		if params.Argon2Memory < 8*uint32(params.Argon2Parallelism) {
+			return nil, errors.New("argon2id memory must be at least 8 KiB per thread")
+		}
+	case AlgorithmBcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password algorithm %q", params.Algorithm)
+	}
+	return &Hasher{params: params}, nil
+}
+
+// Hash returns an encoded hash of password with a random salt.
+func (h *Hasher) Hash(password string) (string, error) {
+	if len(password) > MaxLength {
+		return "", fmt.Errorf("password is longer than %d bytes", MaxLength)
+	}
+	if h.params.Algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.params.BcryptCost)
+		return string(hash), err
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := argon2Params{memory: h.params.Argon2Memory, iterations: h.params.Argon2Iterations, parallelism: h.params.Argon2Parallelism}
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, argon2KeyLength)
+	return p.encode(salt, key), nil
+}
+
+// Verify reports whether password matches encoded and, when it does, whether
+// encoded was produced with a different algorithm or parameters than h uses.
+// Comparison is constant-time.
+func (h *Hasher) Verify(password, encoded string) (match, needsRehash bool, err error) {
+	if strings.HasPrefix(encoded, "$"+AlgorithmArgon2id+"$") {
+		p, salt, key, err := decodeArgon2(encoded)
+		if err != nil {
+			return false, false, err
+		}
+		candidate := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(candidate, key) != 1 {
+			return false, false, nil
+		}
+		current := h.params.Algorithm == AlgorithmArgon2id &&
+			p == argon2Params{memory: h.params.Argon2Memory, iterations: h.params.Argon2Iterations, parallelism: h.params.Argon2Parallelism} &&
+			len(salt) == argon2SaltLength && len(key) == argon2KeyLength
+		return true, !current, nil
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return false, false, ErrMalformedHash
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	current := h.params.Algorithm == AlgorithmBcrypt && cost == h.params.BcryptCost
+	return true, !current, nil
+}
+
+// VerifyDummy takes as long as verifying password against a hash made with
+// the current parameters. Call it when there is no stored hash, e.g. for an
+// unknown username, so response times do not reveal which accounts exist.
+func (h *Hasher) VerifyDummy(password string) {
+	h.dummyOnce.Do(func() {
+		h.dummy, _ = h.Hash("dummy password")
+	})
+	_, _, _ = h.Verify(password, h.dummy)
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// encode uses the PHC string format shared by the reference implementation:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+func (p argon2Params) encode(salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", AlgorithmArgon2id, argon2.Version,
+		p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decodeArgon2(encoded string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, ErrMalformedHash
+	}
+	if p.iterations < 1 || p.parallelism < 1 {
+		return p, nil, nil, ErrMalformedHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return p, nil, nil, ErrMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrMalformedHash
+	}
+	return p, salt, key, nil
+}
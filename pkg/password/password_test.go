@@ -0,0 +1,133 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Small parameters keep the tests fast; production uses DefaultParams.
+var (
+	testArgon2 = Params{Algorithm: AlgorithmArgon2id, Argon2Memory: 64, Argon2Iterations: 1, Argon2Parallelism: 1}
+	testBcrypt = Params{Algorithm: AlgorithmBcrypt, BcryptCost: 4}
+)
+
+func newTestHasher(t *testing.T, params Params) *Hasher {
+	hasher, err := NewHasher(params)
+	require.NoError(t, err)
+	return hasher
+}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	for _, params := range []Params{testArgon2, testBcrypt} {
+		t.Run(params.Algorithm, func(t *testing.T) {
+			hasher := newTestHasher(t, params)
+
+			hash, err := hasher.Hash("correct-Horse-battery")
+			require.NoError(t, err)
+			assert.NotContains(t, hash, "correct-Horse-battery")
+
+			again, err := hasher.Hash("correct-Horse-battery")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, again, "every hash gets its own salt")
+
+			match, needsRehash, err := hasher.Verify("correct-Horse-battery", hash)
+			assert.NoError(t, err)
+			assert.True(t, match)
+			assert.False(t, needsRehash)
+
+			match, needsRehash, err = hasher.Verify("wrong-Horse-battery", hash)
+			assert.NoError(t, err)
+			assert.False(t, match)
+			assert.False(t, needsRehash)
+		})
+	}
+}
+
+func TestHasher_ArgonEncoding(t *testing.T) {
+	hash, err := newTestHasher(t, testArgon2).Hash("correct-Horse-battery")
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	stronger := testArgon2
+	stronger.Argon2Iterations = 2
+	costlier := testBcrypt
+	costlier.BcryptCost = 5
+
+	tests := []struct {
+		name    string
+		from    Params
+		to      Params
+		outdate bool
+	}{
+		{name: "same argon2id parameters", from: testArgon2, to: testArgon2},
+		{name: "argon2id iterations changed", from: testArgon2, to: stronger, outdate: true},
+		{name: "same bcrypt cost", from: testBcrypt, to: testBcrypt},
+		{name: "bcrypt cost changed", from: testBcrypt, to: costlier, outdate: true},
+		{name: "bcrypt to argon2id", from: testBcrypt, to: testArgon2, outdate: true},
+		{name: "argon2id to bcrypt", from: testArgon2, to: testBcrypt, outdate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := newTestHasher(t, tt.from).Hash("correct-Horse-battery")
+			require.NoError(t, err)
+
+			match, needsRehash, err := newTestHasher(t, tt.to).Verify("correct-Horse-battery", hash)
+
+			assert.NoError(t, err)
+			assert.True(t, match)
+			assert.Equal(t, tt.outdate, needsRehash)
+		})
+	}
+}
+
+func TestHasher_MalformedHash(t *testing.T) {
+	hasher := newTestHasher(t, testArgon2)
+
+	for _, hash := range []string{
+		"",
+		"plain text",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		match, _, err := hasher.Verify("correct-Horse-battery", hash)
+		assert.ErrorIs(t, err, ErrMalformedHash, hash)
+		assert.False(t, match, hash)
+	}
+}
+
+func TestHasher_TooLong(t *testing.T) {
+	_, err := newTestHasher(t, testBcrypt).Hash(strings.Repeat("a", MaxLength+1))
+
+	assert.ErrorContains(t, err, "longer than 72 bytes")
+}
+
+func TestHasher_VerifyDummy(t *testing.T) {
+	hasher := newTestHasher(t, testBcrypt)
+
+	hasher.VerifyDummy("anything")
+
+	assert.NotEmpty(t, hasher.dummy)
+}
+
+func TestNewHasher_InvalidParams(t *testing.T) {
+	for _, params := range []Params{
+		{Algorithm: "scrypt"},
+		{Algorithm: AlgorithmArgon2id, Argon2Memory: 64, Argon2Iterations: 0, Argon2Parallelism: 1},
+		{Algorithm: AlgorithmArgon2id, Argon2Memory: 8, Argon2Iterations: 1, Argon2Parallelism: 2},
+		{Algorithm: AlgorithmBcrypt, BcryptCost: 3},
+		{Algorithm: AlgorithmBcrypt, BcryptCost: 32},
+	} {
+		_, err := NewHasher(params)
+		assert.Error(t, err, "%+v", params)
+	}
+	_, err := NewHasher(DefaultParams())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,49 @@
+package problem
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/pkg/requestid"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+type Details struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      string                `json:"code,omitempty"`
+	RequestID string                `json:"request_id,omitempty"`
+	Errors    []apperror.FieldError `json:"errors,omitempty"`
+}
+
+func New(status int, code, detail string) *Details {
+	problemType := "about:blank"
+	if code != "" {
+		problemType = "/problems/" + code
+	}
+	return &Details{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func Write(ctx *gin.Context, details *Details) {
+	if ctx.Request != nil {
+		if details.Instance == "" {
+			details.Instance = ctx.Request.URL.RequestURI()
+		}
+		if details.RequestID == "" {
+			details.RequestID = requestid.FromContext(ctx.Request.Context())
+		}
+	}
+	ctx.Header("Content-Type", ContentType)
+	ctx.AbortWithStatusJSON(details.Status, details)
+}
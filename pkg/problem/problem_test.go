@@ -0,0 +1,46 @@
+package problem
+
+import (
+	"Learn_Jenkins/pkg/requestid"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/7?fields=id", nil)
+	c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), "req-123"))
+
+	Write(c, New(http.StatusNotFound, "user_not_found", "user not found"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.True(t, c.IsAborted())
+
+	var details Details
+	err := json.Unmarshal(w.Body.Bytes(), &details)
+	assert.NoError(t, err)
+	assert.Equal(t, Details{
+		Type:      "/problems/user_not_found",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "user not found",
+		Instance:  "/users/7?fields=id",
+		Code:      "user_not_found",
+		RequestID: "req-123",
+	}, details)
+}
+
+func TestNew_WithoutCode(t *testing.T) {
+	details := New(http.StatusInternalServerError, "", "")
+
+	assert.Equal(t, "about:blank", details.Type)
+	assert.Equal(t, "Internal Server Error", details.Title)
+}
@@ -0,0 +1,71 @@
+// Package requestid carries the per-request correlation ID through
+// context.Context, logs and SQL comments.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+)
+
+const (
+	Header = "X-Request-ID"
+	// LogKey is the attribute name used in logs and the gin context.
+	LogKey = "request_id"
+)
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type contextKey struct{}
+
+// New returns a random 128-bit ID in hex.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Valid reports whether a client-supplied ID is safe to echo in headers,
+// logs and SQL comments.
+func Valid(id string) bool {
+	return validID.MatchString(id)
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler adds the request ID from the record's context to every log
+// line written through next.
+func NewLogHandler(next slog.Handler) slog.Handler {
+	return logHandler{Handler: next}
+}
+
+func (h logHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h logHandler) WithGroup(name string) slog.Handler {
+	return logHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,56 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	id := New()
+
+	assert.Len(t, id, 32)
+	assert.True(t, Valid(id))
+	assert.NotEqual(t, id, New())
+}
+
+func TestValid(t *testing.T) {
+	for _, id := range []string{"req-42", "7f9c2b1a", "trace:span.1_a"} {
+		assert.True(t, Valid(id), id)
+	}
+	for _, id := range []string{"", "has space", "a*/b", "line\nbreak", strings.Repeat("a", 129)} {
+		assert.False(t, Valid(id), id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+
+	ctx := NewContext(context.Background(), "req-1")
+	assert.Equal(t, "req-1", FromContext(ctx))
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	logger.InfoContext(NewContext(context.Background(), "req-7"), "hello")
+	logger.InfoContext(context.Background(), "no id")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "req-7", entry[LogKey])
+	assert.Equal(t, "test", entry["component"])
+
+	entry = nil
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.NotContains(t, entry, LogKey)
+}
@@ -0,0 +1,83 @@
+// Package sealer encrypts the secrets the application must be able to read
+// back, such as the shared secrets of signing clients, before they are
+// stored. It uses AES-256-GCM, so a sealed value that was altered or moved
+// to another record fails to open.
+package sealer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidSealedValue = errors.New("invalid sealed value")
+
+// KeySize is the length of the key in bytes, which selects AES-256.
+const KeySize = 32
+
+// ParseKey decodes a key written as hex, as `openssl rand -hex 32` prints it.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("must be hex encoded")
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+func New(key []byte) (*Sealer, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("sealer: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+func NewRandom() (*Sealer, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return New(key)
+}
+
+// Seal encrypts plaintext under a fresh nonce. The value it is bound to,
+// typically the ID of the record holding it, must be given again to Open.
+func (s *Sealer) Seal(plaintext, boundTo string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), []byte(boundTo))
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Sealer) Open(sealed, boundTo string) (string, error) {
+	raw, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return "", ErrInvalidSealedValue
+	}
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(boundTo))
+	if err != nil {
+		return "", ErrInvalidSealedValue
+	}
+	return string(plaintext), nil
+}
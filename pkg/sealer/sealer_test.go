@@ -0,0 +1,63 @@
+package sealer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte(strings.Repeat("k", KeySize))
+
+func TestSealer_RoundTrip(t *testing.T) {
+	s, err := New(testKey)
+	require.NoError(t, err)
+
+	sealed, err := s.Seal("shared secret", "sc_1")
+	require.NoError(t, err)
+	assert.NotContains(t, sealed, "shared secret")
+
+	opened, err := s.Open(sealed, "sc_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared secret", opened)
+
+	again, err := s.Seal("shared secret", "sc_1")
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "every seal uses a fresh nonce")
+}
+
+func TestSealer_RejectsTamperedValues(t *testing.T) {
+	s, err := New(testKey)
+	require.NoError(t, err)
+	other, err := NewRandom()
+	require.NoError(t, err)
+	sealed, err := s.Seal("shared secret", "sc_1")
+	require.NoError(t, err)
+	foreign, err := other.Seal("shared secret", "sc_1")
+	require.NoError(t, err)
+
+	for name, tt := range map[string]struct{ sealed, boundTo string }{
+		"other record": {sealed, "sc_2"},
+		"other key":    {foreign, "sc_1"},
+		"truncated":    {sealed[:len(sealed)-4], "sc_1"},
+		"too short":    {"AAAA", "sc_1"},
+		"not base64":   {"not base64!", "sc_1"},
+	} {
+		_, err := s.Open(tt.sealed, tt.boundTo)
+		assert.ErrorIs(t, err, ErrInvalidSealedValue, name)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ParseKey(strings.Repeat("ab", KeySize))
+	assert.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	for _, encoded := range []string{"", "zz", strings.Repeat("ab", 16)} {
+		_, err := ParseKey(encoded)
+		assert.Error(t, err, encoded)
+	}
+	_, err = New([]byte("short"))
+	assert.Error(t, err)
+}
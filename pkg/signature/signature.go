@@ -0,0 +1,129 @@
+// Package signature implements the HMAC-SHA256 request signing scheme. A
+// client signs a canonical form of each request with its shared secret and
+// sends
+//
+//	Authorization: HMAC-SHA256 client=<client id>, timestamp=<unix seconds>, nonce=<nonce>, signature=<hex>
+//
+// The string to sign is the scheme name, the method, the escaped path, the
+// query with its parameters sorted, the timestamp, the nonce and the hex
+// SHA-256 of the body, each on its own line. The HMAC key is the secret
+// exactly as issued.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const Scheme = "HMAC-SHA256"
+
+var (
+	ErrMalformed = errors.New("malformed signature credentials")
+
+	noncePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{16,128}$`)
+)
+
+const clientPrefix = "sc_"
+
+// NewClient returns a new client ID and the secret shared with that client.
+func NewClient() (clientID, secret string, err error) {
+	var id [8]byte
+	var key [32]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", "", err
+	}
+	if _, err := rand.Read(key[:]); err != nil {
+		return "", "", err
+	}
+	return clientPrefix + hex.EncodeToString(id[:]), base64.RawURLEncoding.EncodeToString(key[:]), nil
+}
+
+// Credentials are the parameters of the Authorization header.
+type Credentials struct {
+	ClientID  string
+	Timestamp time.Time
+	Nonce     string
+	Signature []byte
+}
+
+// ParseCredentials parses what follows the scheme in the Authorization
+// header. Every parameter is required and may appear only once.
+func ParseCredentials(value string) (*Credentials, error) {
+	params := map[string]string{}
+	for _, part := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || val == "" {
+			return nil, ErrMalformed
+		}
+		if _, seen := params[key]; seen {
+			return nil, ErrMalformed
+		}
+		params[key] = val
+	}
+	if len(params) != 4 {
+		return nil, ErrMalformed
+	}
+
+	timestamp, err := strconv.ParseInt(params["timestamp"], 10, 64)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	signature, err := hex.DecodeString(params["signature"])
+	if err != nil || len(signature) != sha256.Size {
+		return nil, ErrMalformed
+	}
+	if params["client"] == "" || !noncePattern.MatchString(params["nonce"]) {
+		return nil, ErrMalformed
+	}
+	return &Credentials{
+		ClientID:  params["client"],
+		Timestamp: time.Unix(timestamp, 0).UTC(),
+		Nonce:     params["nonce"],
+		Signature: signature,
+	}, nil
+}
+
+// StringToSign builds the canonical form of r, whose body has already been
+// read into body.
+func StringToSign(r *http.Request, timestamp time.Time, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		Scheme,
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.Query().Encode(),
+		strconv.FormatInt(timestamp.Unix(), 10),
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+func Sign(secret []byte, stringToSign string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(stringToSign))
+	return mac.Sum(nil)
+}
+
+// Verify reports, in constant time, whether signature is the signature of
+// stringToSign with secret.
+func Verify(secret []byte, stringToSign string, signature []byte) bool {
+	return hmac.Equal(Sign(secret, stringToSign), signature)
+}
+
+// Authorization returns the Authorization header value a client sends for a
+// request whose string to sign is stringToSign.
+func Authorization(clientID string, secret []byte, timestamp time.Time, nonce, stringToSign string) string {
+	return Scheme + " client=" + clientID +
+		", timestamp=" + strconv.FormatInt(timestamp.Unix(), 10) +
+		", nonce=" + nonce +
+		", signature=" + hex.EncodeToString(Sign(secret, stringToSign))
+}
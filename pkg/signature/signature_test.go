@@ -0,0 +1,85 @@
+package signature
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+const testNonce = "0123456789abcdef"
+
+func TestStringToSign(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/users/a%20b?sort=-id&limit=5", nil)
+
+	got := StringToSign(req, testTime, testNonce, []byte(`{"username":"arthur"}`))
+
+	assert.Equal(t, strings.Join([]string{
+		"HMAC-SHA256",
+		"POST",
+		"/users/a%20b",
+		"limit=5&sort=-id",
+		"1735732800",
+		testNonce,
+		"2ed3f8cb784240c50bc52041f871331691bc29a6fdb45202a4099f7dd73fb083",
+	}, "\n"), got)
+}
+
+func TestAuthorization_RoundTrip(t *testing.T) {
+	secret := []byte("shared-secret")
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	stringToSign := StringToSign(req, testTime, testNonce, nil)
+
+	header := Authorization("sc_client", secret, testTime, testNonce, stringToSign)
+	scheme, params, _ := strings.Cut(header, " ")
+	credentials, err := ParseCredentials(params)
+
+	require.NoError(t, err)
+	assert.Equal(t, Scheme, scheme)
+	assert.Equal(t, "sc_client", credentials.ClientID)
+	assert.Equal(t, testTime, credentials.Timestamp)
+	assert.Equal(t, testNonce, credentials.Nonce)
+	assert.True(t, Verify(secret, stringToSign, credentials.Signature))
+	assert.False(t, Verify([]byte("other-secret"), stringToSign, credentials.Signature))
+	assert.False(t, Verify(secret, StringToSign(req, testTime, testNonce, []byte("tampered")), credentials.Signature))
+}
+
+func TestParseCredentials_Malformed(t *testing.T) {
+	signature := hex.EncodeToString(make([]byte, 32))
+	valid := "client=sc_client, timestamp=1735732800, nonce=" + testNonce + ", signature=" + signature
+	_, err := ParseCredentials(valid)
+	require.NoError(t, err)
+
+	for _, value := range []string{
+		"",
+		"client=sc_client, timestamp=1735732800, nonce=" + testNonce,
+		"client=sc_client, timestamp=yesterday, nonce=" + testNonce + ", signature=" + signature,
+		"client=sc_client, timestamp=1735732800, nonce=short, signature=" + signature,
+		"client=sc_client, timestamp=1735732800, nonce=" + testNonce + ", signature=abcd",
+		"client=sc_client, client=sc_other, nonce=" + testNonce + ", signature=" + signature,
+		"client=sc_client, timestamp=1735732800, nonce=" + testNonce + ", signature=" + signature + ", extra=1",
+		"client=, timestamp=1735732800, nonce=" + testNonce + ", signature=" + signature,
+	} {
+		_, err := ParseCredentials(value)
+		assert.ErrorIs(t, err, ErrMalformed, value)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	clientID, secret, err := NewClient()
+	require.NoError(t, err)
+	otherID, otherSecret, err := NewClient()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^sc_[0-9a-f]{16}$`, clientID)
+	assert.Len(t, secret, 43)
+	assert.NotEqual(t, clientID, otherID)
+	assert.NotEqual(t, secret, otherSecret)
+}
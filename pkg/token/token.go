@@ -0,0 +1,99 @@
+// Package token issues and verifies the short-lived JWT access tokens. They
+// are signed with HMAC-SHA256 and are not stored, so they stay valid until
+// they expire.
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid access token")
+
+// MinSecretLength is the shortest accepted signing key, in bytes, matching
+// the size of the SHA-256 output.
+const MinSecretLength = 32
+
+type Claims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the subject as a user ID.
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 32)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return uint(id), nil
+}
+
+type Manager struct {
+	secret []byte
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewManager(secret []byte, issuer string, ttl time.Duration) *Manager {
+	return &Manager{secret: secret, issuer: issuer, ttl: ttl, now: time.Now}
+}
+
+func NewRandomManager(issuer string, ttl time.Duration) (*Manager, error) {
+	secret := make([]byte, MinSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return NewManager(secret, issuer, ttl), nil
+}
+
+// TTL is how long issued tokens stay valid.
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+func (m *Manager) Issue(userID uint, username string) (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	now := m.now()
+	claims := Claims{
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(id[:]),
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+}
+
+// Parse verifies the signature, issuer and lifetime of token. Every failure
+// is reported as ErrInvalidToken.
+func (m *Manager) Parse(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if _, err := claims.UserID(); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
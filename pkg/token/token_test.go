@@ -0,0 +1,86 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestManager_RoundTrip(t *testing.T) {
+	manager := NewManager(testSecret, "learn_jenkins", 15*time.Minute)
+
+	token, err := manager.Issue(7, "arthur")
+	assert.NoError(t, err)
+
+	claims, err := manager.Parse(token)
+	assert.NoError(t, err)
+	id, err := claims.UserID()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), id)
+	assert.Equal(t, "arthur", claims.Username)
+	assert.Equal(t, "learn_jenkins", claims.Issuer)
+	assert.NotEmpty(t, claims.ID)
+	assert.Equal(t, 15*time.Minute, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+}
+
+func TestManager_RejectsExpiredToken(t *testing.T) {
+	manager := NewManager(testSecret, "learn_jenkins", time.Minute)
+	issued := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return issued }
+
+	token, err := manager.Issue(7, "arthur")
+	assert.NoError(t, err)
+
+	manager.now = func() time.Time { return issued.Add(59 * time.Second) }
+	_, err = manager.Parse(token)
+	assert.NoError(t, err)
+
+	manager.now = func() time.Time { return issued.Add(time.Minute) }
+	_, err = manager.Parse(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestManager_RejectsForeignTokens(t *testing.T) {
+	manager := NewManager(testSecret, "learn_jenkins", time.Minute)
+	token, err := manager.Issue(7, "arthur")
+	assert.NoError(t, err)
+
+	otherSecret, err := NewManager([]byte("ffffffffffffffffffffffffffffffff"), "learn_jenkins", time.Minute).Issue(7, "arthur")
+	assert.NoError(t, err)
+	otherIssuer, err := NewManager(testSecret, "someone_else", time.Minute).Issue(7, "arthur")
+	assert.NoError(t, err)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "learn_jenkins",
+		Subject:   "7",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	noExpiry, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:  "learn_jenkins",
+		Subject: "7",
+	}).SignedString(testSecret)
+	assert.NoError(t, err)
+	badSubject, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    "learn_jenkins",
+		Subject:   "arthur",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(testSecret)
+	assert.NoError(t, err)
+
+	for name, forged := range map[string]string{
+		"other secret": otherSecret,
+		"other issuer": otherIssuer,
+		"alg none":     unsigned,
+		"no expiry":    noExpiry,
+		"bad subject":  badSubject,
+		"truncated":    token[:len(token)-2],
+		"garbage":      "not-a-token",
+	} {
+		_, err := manager.Parse(forged)
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
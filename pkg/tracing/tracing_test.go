@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func restoreProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	restoreProvider(t)
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterFile, File: path, ServiceName: "tracing-test", SampleRatio: 1})
+	require.NoError(t, err)
+	_, span := Tracer().Start(context.Background(), "work")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var exported struct{ Name string }
+	require.NoError(t, json.Unmarshal(content, &exported))
+	assert.Equal(t, "work", exported.Name)
+	assert.Contains(t, string(content), `{"Key":"service.name","Value":{"Type":"STRING","Value":"tracing-test"}}`)
+}
+
+func TestSetup_SampleRatioZeroRecordsNothing(t *testing.T) {
+	restoreProvider(t)
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterFile, File: path, SampleRatio: 0})
+	require.NoError(t, err)
+	_, span := Tracer().Start(context.Background(), "work")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func TestSetup_None(t *testing.T) {
+	restoreProvider(t)
+
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterNone})
+
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	restoreProvider(t)
+
+	_, err := Setup(context.Background(), Options{Exporter: "zipkin"})
+
+	assert.ErrorContains(t, err, `unsupported trace exporter "zipkin"`)
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+	assert.Len(t, spans[1].Events(), 1)
+}
@@ -0,0 +1,226 @@
+package validation
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/pkg/password"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	idtranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+const DefaultLocale = "en"
+
+var (
+	usernamePattern   = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	reservedUsernames = map[string]struct{}{
+		"admin":         {},
+		"administrator": {},
+		"api":           {},
+		"me":            {},
+		"null":          {},
+		"root":          {},
+		"support":       {},
+		"system":        {},
+	}
+)
+
+type Validator struct {
+	validate *validator.Validate
+	uni      *ut.UniversalTranslator
+}
+
+type customRule struct {
+	tag          string
+	fn           validator.Func
+	translations map[string]string
+}
+
+var customRules = []customRule{
+	{
+		tag: "username",
+		fn: func(fl validator.FieldLevel) bool {
+			return usernamePattern.MatchString(fl.Field().String())
+		},
+		translations: map[string]string{
+			"en": "{0} may only contain letters, digits, '.', '_' and '-'",
+			"id": "{0} hanya boleh berisi huruf, angka, '.', '_' dan '-'",
+		},
+	},
+	{
+		tag: "not_reserved",
+		fn: func(fl validator.FieldLevel) bool {
+			_, reserved := reservedUsernames[strings.ToLower(fl.Field().String())]
+			return !reserved
+		},
+		translations: map[string]string{
+			"en": "{0} is reserved and cannot be used",
+			"id": "{0} sudah dicadangkan dan tidak dapat digunakan",
+		},
+	},
+	{
+		tag: "password",
+		fn: func(fl validator.FieldLevel) bool {
+			value := fl.Field().String()
+			return len(value) <= password.MaxLength && characterClasses(value) >= 3
+		},
+		translations: map[string]string{
+			"en": "{0} must be at most 72 bytes and mix at least three of lowercase letters, uppercase letters, digits and symbols",
+			"id": "{0} maksimal 72 byte dan harus memadukan minimal tiga dari huruf kecil, huruf besar, angka dan simbol",
+		},
+	},
+	{
+		tag: "permission",
+		fn: func(fl validator.FieldLevel) bool {
+			return authz.Permission(fl.Field().String()).Valid()
+		},
+		translations: map[string]string{
+			"en": "{0} must be a known permission",
+			"id": "{0} harus berupa izin yang dikenal",
+		},
+	},
+}
+
+// characterClasses counts how many of lowercase letters, uppercase letters,
+// digits and other characters appear in value.
+func characterClasses(value string) int {
+	var lower, upper, digit, other int
+	for _, r := range value {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
+
+func New() (*Validator, error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	english := en.New()
+	uni := ut.New(english, english, id.New())
+
+	enTrans, _ := uni.GetTranslator("en")
+	if err := entranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		return nil, err
+	}
+	idTrans, _ := uni.GetTranslator("id")
+	if err := idtranslations.RegisterDefaultTranslations(validate, idTrans); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range customRules {
+		if err := validate.RegisterValidation(rule.tag, rule.fn); err != nil {
+			return nil, err
+		}
+		for locale, text := range rule.translations {
+			trans, _ := uni.GetTranslator(locale)
+			if err := validate.RegisterTranslation(rule.tag, trans, registerFunc(rule.tag, text), translateFunc(rule.tag)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &Validator{validate: validate, uni: uni}, nil
+}
+
+func (v *Validator) Struct(s interface{}) error {
+	return v.validate.Struct(s)
+}
+
+func (v *Validator) Translator(acceptLanguage string) ut.Translator {
+	trans, _ := v.uni.FindTranslator(parseAcceptLanguage(acceptLanguage)...)
+	return trans
+}
+
+func (v *Validator) FieldErrors(err error, acceptLanguage string) ([]apperror.FieldError, bool) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil, false
+	}
+
+	trans := v.Translator(acceptLanguage)
+	fields := make([]apperror.FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, apperror.FieldError{
+			Field:   fieldErr.Field(),
+			Code:    fieldErr.Tag(),
+			Message: fieldErr.Translate(trans),
+		})
+	}
+	return fields, true
+}
+
+func registerFunc(tag, text string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, text, true)
+	}
+}
+
+func translateFunc(tag string) validator.TranslationFunc {
+	return func(trans ut.Translator, fe validator.FieldError) string {
+		message, err := trans.T(tag, fe.Field())
+		if err != nil {
+			return fe.Error()
+		}
+		return message
+	}
+}
+
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		languages = append(languages, weighted{locale: base, q: q})
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+
+	locales := make([]string, 0, len(languages)+1)
+	for _, language := range languages {
+		locales = append(locales, language.locale)
+	}
+	return append(locales, DefaultLocale)
+}
@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signup struct {
+	Username string `json:"username" validate:"required,min=3,max=32,username,not_reserved"`
+}
+
+func newTestValidator(t *testing.T) *Validator {
+	v, err := New()
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+	return v
+}
+
+func TestValidator_UsernameRules(t *testing.T) {
+	v := newTestValidator(t)
+
+	tests := []struct {
+		username string
+		code     string
+	}{
+		{username: "arthur.h_z-1", code: ""},
+		{username: "", code: "required"},
+		{username: "ab", code: "min"},
+		{username: "arthur hozanna", code: "username"},
+		{username: "Admin", code: "not_reserved"},
+	}
+	for _, tt := range tests {
+		err := v.Struct(&signup{Username: tt.username})
+		if tt.code == "" {
+			assert.NoError(t, err, tt.username)
+			continue
+		}
+		fields, ok := v.FieldErrors(err, "")
+		assert.True(t, ok, tt.username)
+		assert.Len(t, fields, 1, tt.username)
+		assert.Equal(t, "username", fields[0].Field, tt.username)
+		assert.Equal(t, tt.code, fields[0].Code, tt.username)
+	}
+}
+
+type credentials struct {
+	Password string `json:"password" validate:"required,min=12,password"`
+}
+
+func TestValidator_PasswordRules(t *testing.T) {
+	v := newTestValidator(t)
+
+	tests := []struct {
+		password string
+		code     string
+	}{
+		{password: "correct-Horse-battery", code: ""},
+		{password: "Tr0ub4dor&3x", code: ""},
+		{password: "kata sandi Rahasia", code: ""},
+		{password: "", code: "required"},
+		{password: "Sh0rt!", code: "min"},
+		{password: "alllowercaseletters", code: "password"},
+		{password: "lowercase-and-symbols", code: "password"},
+		{password: "Aa1" + strings.Repeat("x", 70), code: "password"},
+	}
+	for _, tt := range tests {
+		err := v.Struct(&credentials{Password: tt.password})
+		if tt.code == "" {
+			assert.NoError(t, err, tt.password)
+			continue
+		}
+		fields, ok := v.FieldErrors(err, "")
+		assert.True(t, ok, tt.password)
+		assert.Len(t, fields, 1, tt.password)
+		assert.Equal(t, "password", fields[0].Field, tt.password)
+		assert.Equal(t, tt.code, fields[0].Code, tt.password)
+	}
+}
+
+type grant struct {
+	Scopes []string `json:"scopes" validate:"dive,permission"`
+}
+
+func TestValidator_PermissionRule(t *testing.T) {
+	v := newTestValidator(t)
+
+	assert.NoError(t, v.Struct(&grant{Scopes: []string{"users:read_all", "users:update"}}))
+
+	err := v.Struct(&grant{Scopes: []string{"users:read_all", "users:*"}})
+	fields, ok := v.FieldErrors(err, "")
+	assert.True(t, ok)
+	assert.Len(t, fields, 1)
+	assert.Equal(t, "scopes[1]", fields[0].Field)
+	assert.Equal(t, "permission", fields[0].Code)
+	assert.Equal(t, "scopes[1] must be a known permission", fields[0].Message)
+}
+
+func TestValidator_TranslatesMessages(t *testing.T) {
+	v := newTestValidator(t)
+	err := v.Struct(&signup{})
+
+	fields, ok := v.FieldErrors(err, "fr-FR, id;q=0.8, en;q=0.5")
+	assert.True(t, ok)
+	assert.Equal(t, "username wajib diisi", fields[0].Message)
+
+	fields, _ = v.FieldErrors(err, "en-US")
+	assert.Equal(t, "username is a required field", fields[0].Message)
+
+	err = v.Struct(&signup{Username: "root"})
+	fields, _ = v.FieldErrors(err, "id")
+	assert.Equal(t, "username sudah dicadangkan dan tidak dapat digunakan", fields[0].Message)
+}
+
+func TestValidator_FieldErrorsIgnoresOtherErrors(t *testing.T) {
+	v := newTestValidator(t)
+
+	fields, ok := v.FieldErrors(assert.AnError, "en")
+	assert.False(t, ok)
+	assert.Nil(t, fields)
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"id", "en", "en"}, parseAcceptLanguage("en;q=0.4, id-ID"))
+	assert.Equal(t, []string{"en"}, parseAcceptLanguage(""))
+}
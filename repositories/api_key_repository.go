@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"time"
+)
+
+var ErrAPIKeyNotFound = apperror.NotFound("api_key_not_found", "API key not found")
+
+//go:generate go run go.uber.org/mock/mockgen -source=api_key_repository.go -destination=mocks/api_key_repository_mock.go -package=mocks
+
+type APIKeyRepository interface {
+	// CreateAPIKey fails with ErrUserNotFound when the owner does not exist.
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	// FindAPIKeysByUserID returns the keys of a user, revoked ones included,
+	// oldest first. It fails with ErrUserNotFound for unknown users.
+	FindAPIKeysByUserID(ctx context.Context, userID uint) ([]*model.APIKey, error)
+	// RevokeAPIKey fails with ErrAPIKeyNotFound unless the key belongs to
+	// userID. Revoking a revoked key keeps its original revocation time.
+	RevokeAPIKey(ctx context.Context, userID, id uint, at time.Time) error
+	TouchAPIKey(ctx context.Context, id uint, at time.Time) error
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepositoryImpl{db: db}
+}
+
+func (r *apiKeyRepositoryImpl) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	if err := createCredential(r.db.WithContext(ctx), key.UserID, key); err != nil {
+		return translateAPIKeyError(err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, translateAPIKeyError(err)
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepositoryImpl) FindAPIKeysByUserID(ctx context.Context, userID uint) ([]*model.APIKey, error) {
+	keys, err := findCredentialsByUserID[model.APIKey](r.db.WithContext(ctx), userID)
+	if err != nil {
+		return nil, translateAPIKeyError(err)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepositoryImpl) RevokeAPIKey(ctx context.Context, userID, id uint, at time.Time) error {
+	if err := revokeCredential[model.APIKey](r.db.WithContext(ctx), userID, id, at); err != nil {
+		return translateAPIKeyError(err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) TouchAPIKey(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", id).Update("last_used_at", at)
+	if result.Error != nil {
+		return translateAPIKeyError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func translateAPIKeyError(err error) error {
+	return translateCredentialError(err, ErrAPIKeyNotFound)
+}
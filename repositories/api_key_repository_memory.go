@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/model"
+	"context"
+	"time"
+)
+
+// apiKeyRepositoryMemory mirrors apiKeyRepositoryImpl on a credentialStore.
+type apiKeyRepositoryMemory struct {
+	keys *credentialStore[model.APIKey, *model.APIKey]
+}
+
+func NewMemoryAPIKeyRepository(users UserRepository) APIKeyRepository {
+	return &apiKeyRepositoryMemory{keys: newCredentialStore[model.APIKey](users, ErrAPIKeyNotFound)}
+}
+
+func (r *apiKeyRepositoryMemory) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	return r.keys.create(ctx, key, func(existing *model.APIKey) bool {
+		return existing.Prefix == key.Prefix
+	})
+}
+
+func (r *apiKeyRepositoryMemory) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	return r.keys.find(ctx, func(key *model.APIKey) bool {
+		return key.Prefix == prefix
+	})
+}
+
+func (r *apiKeyRepositoryMemory) FindAPIKeysByUserID(ctx context.Context, userID uint) ([]*model.APIKey, error) {
+	return r.keys.findByUserID(ctx, userID)
+}
+
+func (r *apiKeyRepositoryMemory) RevokeAPIKey(ctx context.Context, userID, id uint, at time.Time) error {
+	return r.keys.revoke(ctx, userID, id, at)
+}
+
+func (r *apiKeyRepositoryMemory) TouchAPIKey(ctx context.Context, id uint, at time.Time) error {
+	return r.keys.update(ctx, id, func(key *model.APIKey) error {
+		usedAt := at
+		key.LastUsedAt = &usedAt
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/repositories/repositorytest"
+	"testing"
+)
+
+func TestUserRepository_Conformance(t *testing.T) {
+	repositorytest.Run(t, repositories.NewTestUserRepository)
+}
+
+func TestMemoryUserRepository_Conformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositories.UserRepository {
+		return repositories.NewMemoryUserRepository()
+	})
+}
+
+func TestRefreshTokenRepository_Conformance(t *testing.T) {
+	repositorytest.RunRefreshTokens(t, repositories.NewTestRefreshTokenRepository)
+}
+
+func TestMemoryRefreshTokenRepository_Conformance(t *testing.T) {
+	repositorytest.RunRefreshTokens(t, func(t *testing.T) (repositories.UserRepository, repositories.RefreshTokenRepository) {
+		return repositories.NewMemoryUserRepository(), repositories.NewMemoryRefreshTokenRepository()
+	})
+}
+
+func TestRoleRepository_Conformance(t *testing.T) {
+	repositorytest.RunRoles(t, repositories.NewTestRoleRepository)
+}
+
+func TestMemoryRoleRepository_Conformance(t *testing.T) {
+	repositorytest.RunRoles(t, func(t *testing.T) (repositories.UserRepository, repositories.RoleRepository) {
+		users := repositories.NewMemoryUserRepository()
+		return users, repositories.NewMemoryRoleRepository(users)
+	})
+}
+
+func TestAPIKeyRepository_Conformance(t *testing.T) {
+	repositorytest.RunAPIKeys(t, repositories.NewTestAPIKeyRepository)
+}
+
+func TestMemoryAPIKeyRepository_Conformance(t *testing.T) {
+	repositorytest.RunAPIKeys(t, func(t *testing.T) (repositories.UserRepository, repositories.APIKeyRepository) {
+		users := repositories.NewMemoryUserRepository()
+		return users, repositories.NewMemoryAPIKeyRepository(users)
+	})
+}
+
+func TestSigningClientRepository_Conformance(t *testing.T) {
+	repositorytest.RunSigningClients(t, repositories.NewTestSigningClientRepository)
+}
+
+func TestMemorySigningClientRepository_Conformance(t *testing.T) {
+	repositorytest.RunSigningClients(t, func(t *testing.T) (repositories.UserRepository, repositories.SigningClientRepository) {
+		users := repositories.NewMemoryUserRepository()
+		return users, repositories.NewMemorySigningClientRepository(users)
+	})
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The helpers below hold the queries API keys and signing clients share.
+// T is the model, whose table has the columns of model.Credential; errors
+// are left for the caller to translate.
+
+// createCredential fails with ErrUserNotFound when the owner does not exist.
+func createCredential[T any](db *gorm.DB, userID uint, credential *T) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := userExists(tx, userID); err != nil {
+			return err
+		}
+		return tx.Create(credential).Error
+	})
+}
+
+// findCredentialsByUserID returns the credentials of a user, revoked ones
+// included, oldest first.
+func findCredentialsByUserID[T any](db *gorm.DB, userID uint) ([]*T, error) {
+	var credentials []*T
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := userExists(tx, userID); err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Order("id").Find(&credentials).Error
+	})
+	return credentials, err
+}
+
+// revokeCredential fails with gorm.ErrRecordNotFound unless the credential
+// belongs to userID, and keeps the original time of a revoked one.
+func revokeCredential[T any](db *gorm.DB, userID, id uint, at time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(new(T)).Error; err != nil {
+			return err
+		}
+		return tx.Model(new(T)).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", at).Error
+	})
+}
+
+// translateCredentialError maps a missing row to notFound.
+func translateCredentialError(err error, notFound error) error {
+	var appErr *apperror.Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return notFound
+	default:
+		return apperror.Internal(err)
+	}
+}
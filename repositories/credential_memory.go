@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// credentialRecord is satisfied by *model.APIKey and *model.SigningClient.
+type credentialRecord[T any] interface {
+	*T
+	Base() *model.Credential
+}
+
+// credentialStore keeps API keys or signing clients in a map guarded by a
+// mutex, mirroring credential_impl.go. Owners are looked up through the user
+// repository; unlike the database, credentials are not removed when their
+// owner is deleted. Records are copied in and out, so callers never share
+// them.
+type credentialStore[T any, P credentialRecord[T]] struct {
+	users    UserRepository
+	notFound error
+
+	mu      sync.Mutex
+	nextID  uint
+	records map[uint]T
+}
+
+func newCredentialStore[T any, P credentialRecord[T]](users UserRepository, notFound error) *credentialStore[T, P] {
+	return &credentialStore[T, P]{users: users, notFound: notFound, records: map[uint]T{}}
+}
+
+// create assigns the next ID to credential and stores it, unless clashes
+// reports that an existing record holds the same public identifier.
+func (s *credentialStore[T, P]) create(ctx context.Context, credential P, clashes func(existing P) bool) error {
+	if _, err := s.users.FindUserByID(ctx, credential.Base().UserID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.records {
+		if clashes(&existing) {
+			return apperror.Internal(errors.New("duplicate credential identifier"))
+		}
+	}
+	s.nextID++
+	credential.Base().ID = s.nextID
+	s.records[s.nextID] = *credential
+	return nil
+}
+
+func (s *credentialStore[T, P]) find(ctx context.Context, match func(P) bool) (P, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range s.records {
+		if match(&record) {
+			return &record, nil
+		}
+	}
+	return nil, s.notFound
+}
+
+func (s *credentialStore[T, P]) findByUserID(ctx context.Context, userID uint) ([]P, error) {
+	if _, err := s.users.FindUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := []P{}
+	for _, record := range s.records {
+		if P(&record).Base().UserID == userID {
+			records = append(records, &record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Base().ID < records[j].Base().ID })
+	return records, nil
+}
+
+func (s *credentialStore[T, P]) revoke(ctx context.Context, userID, id uint, at time.Time) error {
+	return s.update(ctx, id, func(record P) error {
+		credential := record.Base()
+		if credential.UserID != userID {
+			return s.notFound
+		}
+		if credential.RevokedAt == nil {
+			revokedAt := at
+			credential.RevokedAt = &revokedAt
+		}
+		return nil
+	})
+}
+
+// update applies change to the record id, and saves it unless change fails.
+func (s *credentialStore[T, P]) update(ctx context.Context, id uint, change func(P) error) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return s.notFound
+	}
+	if err := change(&record); err != nil {
+		return err
+	}
+	s.records[id] = record
+	return nil
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import "testing"
+
+func NewTestUserRepository(t *testing.T) UserRepository {
+	return NewUserRepository(setupTestDB(t))
+}
+
+func NewTestRefreshTokenRepository(t *testing.T) (UserRepository, RefreshTokenRepository) {
+	db := setupTestDB(t)
+	return NewUserRepository(db), NewRefreshTokenRepository(db)
+}
+
+func NewTestRoleRepository(t *testing.T) (UserRepository, RoleRepository) {
+	db := setupTestDB(t)
+	return NewUserRepository(db), NewRoleRepository(db)
+}
+
+func NewTestAPIKeyRepository(t *testing.T) (UserRepository, APIKeyRepository) {
+	db := setupTestDB(t)
+	return NewUserRepository(db), NewAPIKeyRepository(db)
+}
+
+func NewTestSigningClientRepository(t *testing.T) (UserRepository, SigningClientRepository) {
+	db := setupTestDB(t)
+	return NewUserRepository(db), NewSigningClientRepository(db)
+}
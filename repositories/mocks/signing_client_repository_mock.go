@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: signing_client_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=signing_client_repository.go -destination=mocks/signing_client_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	model "Learn_Jenkins/domain/model"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSigningClientRepository is a mock of SigningClientRepository interface.
+type MockSigningClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningClientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSigningClientRepositoryMockRecorder is the mock recorder for MockSigningClientRepository.
+type MockSigningClientRepositoryMockRecorder struct {
+	mock *MockSigningClientRepository
+}
+
+// NewMockSigningClientRepository creates a new mock instance.
+func NewMockSigningClientRepository(ctrl *gomock.Controller) *MockSigningClientRepository {
+	mock := &MockSigningClientRepository{ctrl: ctrl}
+	mock.recorder = &MockSigningClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningClientRepository) EXPECT() *MockSigningClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSigningClient mocks base method.
+func (m *MockSigningClientRepository) CreateSigningClient(ctx context.Context, client *model.SigningClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSigningClient", ctx, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSigningClient indicates an expected call of CreateSigningClient.
+func (mr *MockSigningClientRepositoryMockRecorder) CreateSigningClient(ctx, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSigningClient", reflect.TypeOf((*MockSigningClientRepository)(nil).CreateSigningClient), ctx, client)
+}
+
+// FindSigningClientByClientID mocks base method.
+func (m *MockSigningClientRepository) FindSigningClientByClientID(ctx context.Context, clientID string) (*model.SigningClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSigningClientByClientID", ctx, clientID)
+	ret0, _ := ret[0].(*model.SigningClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSigningClientByClientID indicates an expected call of FindSigningClientByClientID.
+func (mr *MockSigningClientRepositoryMockRecorder) FindSigningClientByClientID(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSigningClientByClientID", reflect.TypeOf((*MockSigningClientRepository)(nil).FindSigningClientByClientID), ctx, clientID)
+}
+
+// FindSigningClientsByUserID mocks base method.
+func (m *MockSigningClientRepository) FindSigningClientsByUserID(ctx context.Context, userID uint) ([]*model.SigningClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSigningClientsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*model.SigningClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSigningClientsByUserID indicates an expected call of FindSigningClientsByUserID.
+func (mr *MockSigningClientRepositoryMockRecorder) FindSigningClientsByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSigningClientsByUserID", reflect.TypeOf((*MockSigningClientRepository)(nil).FindSigningClientsByUserID), ctx, userID)
+}
+
+// PurgeExpiredNonces mocks base method.
+func (m *MockSigningClientRepository) PurgeExpiredNonces(ctx context.Context, now time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredNonces", ctx, now, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredNonces indicates an expected call of PurgeExpiredNonces.
+func (mr *MockSigningClientRepositoryMockRecorder) PurgeExpiredNonces(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredNonces", reflect.TypeOf((*MockSigningClientRepository)(nil).PurgeExpiredNonces), ctx, now, limit)
+}
+
+// RevokeSigningClient mocks base method.
+func (m *MockSigningClientRepository) RevokeSigningClient(ctx context.Context, userID, id uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSigningClient", ctx, userID, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSigningClient indicates an expected call of RevokeSigningClient.
+func (mr *MockSigningClientRepositoryMockRecorder) RevokeSigningClient(ctx, userID, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSigningClient", reflect.TypeOf((*MockSigningClientRepository)(nil).RevokeSigningClient), ctx, userID, id, at)
+}
+
+// UseNonce mocks base method.
+func (m *MockSigningClientRepository) UseNonce(ctx context.Context, nonce *model.SignatureNonce) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseNonce", ctx, nonce)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseNonce indicates an expected call of UseNonce.
+func (mr *MockSigningClientRepositoryMockRecorder) UseNonce(ctx, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseNonce", reflect.TypeOf((*MockSigningClientRepository)(nil).UseNonce), ctx, nonce)
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"time"
+)
+
+var (
+	ErrRefreshTokenNotFound = apperror.NotFound("refresh_token_not_found", "refresh token not found")
+	ErrRefreshTokenRevoked  = apperror.Conflict("refresh_token_revoked", "refresh token has already been revoked")
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=refresh_token_repository.go -destination=mocks/refresh_token_repository_mock.go -package=mocks
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	FindRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	// RotateRefreshToken revokes the token with oldID and stores next in one
+	// step. It fails with ErrRefreshTokenRevoked if oldID was already revoked,
+	// so each token can be rotated at most once, even by concurrent requests.
+	RotateRefreshToken(ctx context.Context, oldID uint, next *model.RefreshToken) error
+	// RevokeRefreshTokenFamily revokes every token of the family that is not
+	// revoked yet.
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error
+	// RevokeUserRefreshTokens revokes every token of the user that is not
+	// revoked yet, across all families.
+	RevokeUserRefreshTokens(ctx context.Context, userID uint, at time.Time) error
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{db: db}
+}
+
+func (r *refreshTokenRepositoryImpl) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return translateRefreshTokenError(err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) FindRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, translateRefreshTokenError(err)
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepositoryImpl) RotateRefreshToken(ctx context.Context, oldID uint, next *model.RefreshToken) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The conditional update is what makes rotation single-use: of two
+		// concurrent rotations only one sees a row affected.
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Update("revoked_at", next.CreatedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.Where("id = ?", oldID).First(&model.RefreshToken{}).Error; err != nil {
+				return err
+			}
+			return ErrRefreshTokenRevoked
+		}
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		return translateRefreshTokenError(err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return translateRefreshTokenError(err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeUserRefreshTokens(ctx context.Context, userID uint, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return translateRefreshTokenError(err)
+	}
+	return nil
+}
+
+func translateRefreshTokenError(err error) error {
+	var appErr *apperror.Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrRefreshTokenNotFound
+	default:
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// refreshTokenRepositoryMemory mirrors refreshTokenRepositoryImpl with maps
+// guarded by a mutex. Unlike the database it does not check that the user
+// exists, nor remove tokens when the user is deleted.
+type refreshTokenRepositoryMemory struct {
+	mu     sync.Mutex
+	nextID uint
+	tokens map[uint]model.RefreshToken
+	byHash map[string]uint
+}
+
+func NewMemoryRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepositoryMemory{tokens: map[uint]model.RefreshToken{}, byHash: map[string]uint{}}
+}
+
+func (r *refreshTokenRepositoryMemory) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.create(token)
+}
+
+func (r *refreshTokenRepositoryMemory) FindRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, ok := r.byHash[hash]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+	token := r.tokens[id]
+	return &token, nil
+}
+
+func (r *refreshTokenRepositoryMemory) RotateRefreshToken(ctx context.Context, oldID uint, next *model.RefreshToken) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.tokens[oldID]
+	if !ok {
+		return ErrRefreshTokenNotFound
+	}
+	if old.RevokedAt != nil {
+		return ErrRefreshTokenRevoked
+	}
+	if err := r.create(next); err != nil {
+		return err
+	}
+	revokedAt := next.CreatedAt
+	old.RevokedAt = &revokedAt
+	r.tokens[oldID] = old
+	return nil
+}
+
+func (r *refreshTokenRepositoryMemory) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			revokedAt := at
+			token.RevokedAt = &revokedAt
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (r *refreshTokenRepositoryMemory) RevokeUserRefreshTokens(ctx context.Context, userID uint, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			revokedAt := at
+			token.RevokedAt = &revokedAt
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (r *refreshTokenRepositoryMemory) create(token *model.RefreshToken) error {
+	if _, ok := r.byHash[token.TokenHash]; ok {
+		return apperror.Internal(errors.New("duplicate refresh token hash"))
+	}
+	r.nextID++
+	token.ID = r.nextID
+	r.tokens[token.ID] = *token
+	r.byHash[token.TokenHash] = token.ID
+	return nil
+}
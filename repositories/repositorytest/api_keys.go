@@ -0,0 +1,177 @@
+package repositorytest
+
+import (
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// APIKeyFactory returns empty repositories backed by the same store, so that
+// keys can reference the users created through the first one.
+type APIKeyFactory func(t *testing.T) (repositories.UserRepository, repositories.APIKeyRepository)
+
+// RunAPIKeys checks the behavior shared by all API key backends.
+func RunAPIKeys(t *testing.T, newRepositories APIKeyFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository)
+	}{
+		{"CreateAndFindByPrefix", testCreateAPIKey},
+		{"Create_UnknownUser", testCreateAPIKeyUnknownUser},
+		{"FindByPrefix_NotFound", testFindAPIKeyNotFound},
+		{"FindByUser", testFindAPIKeysByUser},
+		{"FindByUser_UnknownUser", testFindAPIKeysUnknownUser},
+		{"Revoke", testRevokeAPIKey},
+		{"Revoke_OtherUser", testRevokeOtherUsersAPIKey},
+		{"Touch", testTouchAPIKey},
+		{"CanceledContext", testAPIKeyCanceledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, keys := newRepositories(t)
+			tt.run(t, users, keys)
+		})
+	}
+}
+
+// newCredential returns the fields API keys and signing clients share.
+func newCredential(userID uint, name string) model.Credential {
+	return model.Credential{UserID: userID, Name: name, Scopes: "users:read_all", CreatedAt: testTime}
+}
+
+func newAPIKey(userID uint, name, prefix string) *model.APIKey {
+	expiresAt := testTime.Add(24 * time.Hour)
+	return &model.APIKey{
+		Credential: newCredential(userID, name),
+		Prefix:     prefix,
+		KeyHash:    "hash-" + prefix,
+		ExpiresAt:  &expiresAt,
+	}
+}
+
+func createAPIKey(t *testing.T, keys repositories.APIKeyRepository, key *model.APIKey) *model.APIKey {
+	require.NoError(t, keys.CreateAPIKey(context.Background(), key), "create %s", key.Prefix)
+	return key
+}
+
+func findAPIKey(t *testing.T, keys repositories.APIKeyRepository, prefix string) *model.APIKey {
+	key, err := keys.FindAPIKeyByPrefix(context.Background(), prefix)
+	require.NoError(t, err, "find %s", prefix)
+	return key
+}
+
+func testCreateAPIKey(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	user := create(t, users, "arthur")[0]
+	key := createAPIKey(t, keys, newAPIKey(user.ID, "batch", "prefix-1"))
+	assert.NotZero(t, key.ID)
+
+	found := findAPIKey(t, keys, "prefix-1")
+	assert.Equal(t, key.ID, found.ID)
+	assert.Equal(t, user.ID, found.UserID)
+	assert.Equal(t, "batch", found.Name)
+	assert.Equal(t, "hash-prefix-1", found.KeyHash)
+	assert.Equal(t, "users:read_all", found.Scopes)
+	if assert.NotNil(t, found.ExpiresAt) {
+		assert.True(t, key.ExpiresAt.Equal(*found.ExpiresAt), "expires at %s", found.ExpiresAt)
+	}
+	assert.True(t, testTime.Equal(found.CreatedAt), "created at %s", found.CreatedAt)
+	assert.Nil(t, found.LastUsedAt)
+	assert.Nil(t, found.RevokedAt)
+}
+
+func testCreateAPIKeyUnknownUser(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	err := keys.CreateAPIKey(context.Background(), newAPIKey(999, "batch", "prefix-1"))
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	_, err = keys.FindAPIKeyByPrefix(context.Background(), "prefix-1")
+	assert.ErrorIs(t, err, repositories.ErrAPIKeyNotFound)
+}
+
+func testFindAPIKeyNotFound(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	key, err := keys.FindAPIKeyByPrefix(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, repositories.ErrAPIKeyNotFound)
+	assert.Nil(t, key)
+}
+
+func testFindAPIKeysByUser(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	created := create(t, users, "arthur", "ford", "zaphod")
+	createAPIKey(t, keys, newAPIKey(created[0].ID, "first", "prefix-1"))
+	createAPIKey(t, keys, newAPIKey(created[1].ID, "other", "prefix-2"))
+	createAPIKey(t, keys, newAPIKey(created[0].ID, "second", "prefix-3"))
+
+	found, err := keys.FindAPIKeysByUserID(context.Background(), created[0].ID)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "first", found[0].Name)
+	assert.Equal(t, "second", found[1].Name)
+
+	found, err = keys.FindAPIKeysByUserID(context.Background(), created[2].ID)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testFindAPIKeysUnknownUser(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	_, err := keys.FindAPIKeysByUserID(context.Background(), 999)
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+}
+
+func testRevokeAPIKey(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	user := create(t, users, "arthur")[0]
+	key := createAPIKey(t, keys, newAPIKey(user.ID, "batch", "prefix-1"))
+	revokedAt := testTime.Add(time.Hour)
+
+	require.NoError(t, keys.RevokeAPIKey(context.Background(), user.ID, key.ID, revokedAt))
+	require.NoError(t, keys.RevokeAPIKey(context.Background(), user.ID, key.ID, revokedAt.Add(time.Hour)))
+
+	found := findAPIKey(t, keys, "prefix-1")
+	if assert.NotNil(t, found.RevokedAt) {
+		assert.True(t, revokedAt.Equal(*found.RevokedAt), "revoked at %s", found.RevokedAt)
+	}
+}
+
+func testRevokeOtherUsersAPIKey(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	created := create(t, users, "arthur", "ford")
+	key := createAPIKey(t, keys, newAPIKey(created[0].ID, "batch", "prefix-1"))
+
+	assert.ErrorIs(t, keys.RevokeAPIKey(context.Background(), created[1].ID, key.ID, testTime), repositories.ErrAPIKeyNotFound)
+	assert.ErrorIs(t, keys.RevokeAPIKey(context.Background(), created[0].ID, 999, testTime), repositories.ErrAPIKeyNotFound)
+	assert.Nil(t, findAPIKey(t, keys, "prefix-1").RevokedAt)
+}
+
+func testTouchAPIKey(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	user := create(t, users, "arthur")[0]
+	key := createAPIKey(t, keys, newAPIKey(user.ID, "batch", "prefix-1"))
+	usedAt := testTime.Add(time.Minute)
+
+	require.NoError(t, keys.TouchAPIKey(context.Background(), key.ID, usedAt))
+
+	found := findAPIKey(t, keys, "prefix-1")
+	if assert.NotNil(t, found.LastUsedAt) {
+		assert.True(t, usedAt.Equal(*found.LastUsedAt), "last used at %s", found.LastUsedAt)
+	}
+	assert.ErrorIs(t, keys.TouchAPIKey(context.Background(), 999, usedAt), repositories.ErrAPIKeyNotFound)
+}
+
+func testAPIKeyCanceledContext(t *testing.T, users repositories.UserRepository, keys repositories.APIKeyRepository) {
+	user := create(t, users, "arthur")[0]
+	key := createAPIKey(t, keys, newAPIKey(user.ID, "batch", "prefix-1"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, keys.CreateAPIKey(ctx, newAPIKey(user.ID, "batch", "prefix-2")), context.Canceled)
+	_, err := keys.FindAPIKeyByPrefix(ctx, "prefix-1")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = keys.FindAPIKeysByUserID(ctx, user.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, keys.RevokeAPIKey(ctx, user.ID, key.ID, testTime), context.Canceled)
+	assert.ErrorIs(t, keys.TouchAPIKey(ctx, key.ID, testTime), context.Canceled)
+	assert.Nil(t, findAPIKey(t, keys, "prefix-1").RevokedAt)
+}
@@ -0,0 +1,179 @@
+package repositorytest
+
+import (
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RefreshTokenFactory returns empty repositories backed by the same store, so
+// that tokens can reference the users created through the first one.
+type RefreshTokenFactory func(t *testing.T) (repositories.UserRepository, repositories.RefreshTokenRepository)
+
+// RunRefreshTokens checks the behavior shared by all refresh token backends.
+func RunRefreshTokens(t *testing.T, newRepositories RefreshTokenFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository)
+	}{
+		{"CreateAndFindByHash", testCreateRefreshToken},
+		{"FindByHash_NotFound", testFindRefreshTokenNotFound},
+		{"Rotate", testRotateRefreshToken},
+		{"Rotate_AlreadyRevoked", testRotateRevokedRefreshToken},
+		{"Rotate_NotFound", testRotateMissingRefreshToken},
+		{"RevokeFamily", testRevokeRefreshTokenFamily},
+		{"RevokeUser", testRevokeUserRefreshTokens},
+		{"CanceledContext", testRefreshTokenCanceledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, tokens := newRepositories(t)
+			tt.run(t, users, tokens)
+		})
+	}
+}
+
+// testTime is whole seconds in UTC so it survives every backend unchanged.
+var testTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newRefreshToken(userID uint, family, hash string, createdAt time.Time) *model.RefreshToken {
+	return &model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  family,
+		TokenHash: hash,
+		ExpiresAt: createdAt.Add(time.Hour),
+		CreatedAt: createdAt,
+	}
+}
+
+func findToken(t *testing.T, tokens repositories.RefreshTokenRepository, hash string) *model.RefreshToken {
+	token, err := tokens.FindRefreshTokenByHash(context.Background(), hash)
+	require.NoError(t, err, "find %s", hash)
+	return token
+}
+
+func assertRevokedAt(t *testing.T, want time.Time, token *model.RefreshToken) {
+	t.Helper()
+	if assert.NotNil(t, token.RevokedAt, "token %s is not revoked", token.TokenHash) {
+		assert.True(t, want.Equal(*token.RevokedAt), "revoked at %s, want %s", token.RevokedAt, want)
+	}
+}
+
+func testCreateRefreshToken(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	user := create(t, users, "arthur")[0]
+	token := newRefreshToken(user.ID, "family", "hash-1", testTime)
+
+	require.NoError(t, tokens.CreateRefreshToken(context.Background(), token))
+	assert.NotZero(t, token.ID)
+
+	found := findToken(t, tokens, "hash-1")
+	assert.Equal(t, token.ID, found.ID)
+	assert.Equal(t, user.ID, found.UserID)
+	assert.Equal(t, "family", found.FamilyID)
+	assert.True(t, token.ExpiresAt.Equal(found.ExpiresAt), "expires at %s", found.ExpiresAt)
+	assert.Nil(t, found.RevokedAt)
+}
+
+func testFindRefreshTokenNotFound(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	token, err := tokens.FindRefreshTokenByHash(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, repositories.ErrRefreshTokenNotFound)
+	assert.Nil(t, token)
+}
+
+func testRotateRefreshToken(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	user := create(t, users, "arthur")[0]
+	old := newRefreshToken(user.ID, "family", "hash-1", testTime)
+	require.NoError(t, tokens.CreateRefreshToken(context.Background(), old))
+
+	rotatedAt := testTime.Add(time.Minute)
+	next := newRefreshToken(user.ID, "family", "hash-2", rotatedAt)
+	require.NoError(t, tokens.RotateRefreshToken(context.Background(), old.ID, next))
+
+	assert.NotZero(t, next.ID)
+	assertRevokedAt(t, rotatedAt, findToken(t, tokens, "hash-1"))
+	assert.Nil(t, findToken(t, tokens, "hash-2").RevokedAt)
+}
+
+func testRotateRevokedRefreshToken(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	user := create(t, users, "arthur")[0]
+	old := newRefreshToken(user.ID, "family", "hash-1", testTime)
+	require.NoError(t, tokens.CreateRefreshToken(context.Background(), old))
+	require.NoError(t, tokens.RotateRefreshToken(context.Background(), old.ID, newRefreshToken(user.ID, "family", "hash-2", testTime)))
+
+	err := tokens.RotateRefreshToken(context.Background(), old.ID, newRefreshToken(user.ID, "family", "hash-3", testTime))
+
+	assert.ErrorIs(t, err, repositories.ErrRefreshTokenRevoked)
+	_, err = tokens.FindRefreshTokenByHash(context.Background(), "hash-3")
+	assert.ErrorIs(t, err, repositories.ErrRefreshTokenNotFound)
+}
+
+func testRotateMissingRefreshToken(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	user := create(t, users, "arthur")[0]
+
+	err := tokens.RotateRefreshToken(context.Background(), 999, newRefreshToken(user.ID, "family", "hash-1", testTime))
+
+	assert.ErrorIs(t, err, repositories.ErrRefreshTokenNotFound)
+	_, err = tokens.FindRefreshTokenByHash(context.Background(), "hash-1")
+	assert.ErrorIs(t, err, repositories.ErrRefreshTokenNotFound)
+}
+
+func testRevokeRefreshTokenFamily(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	user := create(t, users, "arthur")[0]
+	ctx := context.Background()
+	first := newRefreshToken(user.ID, "family-a", "hash-a1", testTime)
+	require.NoError(t, tokens.CreateRefreshToken(ctx, first))
+	rotatedAt := testTime.Add(time.Minute)
+	require.NoError(t, tokens.RotateRefreshToken(ctx, first.ID, newRefreshToken(user.ID, "family-a", "hash-a2", rotatedAt)))
+	require.NoError(t, tokens.CreateRefreshToken(ctx, newRefreshToken(user.ID, "family-b", "hash-b1", testTime)))
+
+	revokedAt := testTime.Add(time.Hour)
+	require.NoError(t, tokens.RevokeRefreshTokenFamily(ctx, "family-a", revokedAt))
+
+	assertRevokedAt(t, rotatedAt, findToken(t, tokens, "hash-a1"))
+	assertRevokedAt(t, revokedAt, findToken(t, tokens, "hash-a2"))
+	assert.Nil(t, findToken(t, tokens, "hash-b1").RevokedAt)
+	assert.NoError(t, tokens.RevokeRefreshTokenFamily(ctx, "missing", revokedAt))
+}
+
+func testRevokeUserRefreshTokens(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	created := create(t, users, "arthur", "ford")
+	ctx := context.Background()
+	first := newRefreshToken(created[0].ID, "family-a", "hash-a1", testTime)
+	require.NoError(t, tokens.CreateRefreshToken(ctx, first))
+	rotatedAt := testTime.Add(time.Minute)
+	require.NoError(t, tokens.RotateRefreshToken(ctx, first.ID, newRefreshToken(created[0].ID, "family-a", "hash-a2", rotatedAt)))
+	require.NoError(t, tokens.CreateRefreshToken(ctx, newRefreshToken(created[0].ID, "family-b", "hash-b1", testTime)))
+	require.NoError(t, tokens.CreateRefreshToken(ctx, newRefreshToken(created[1].ID, "family-c", "hash-c1", testTime)))
+
+	revokedAt := testTime.Add(time.Hour)
+	require.NoError(t, tokens.RevokeUserRefreshTokens(ctx, created[0].ID, revokedAt))
+
+	assertRevokedAt(t, rotatedAt, findToken(t, tokens, "hash-a1"))
+	assertRevokedAt(t, revokedAt, findToken(t, tokens, "hash-a2"))
+	assertRevokedAt(t, revokedAt, findToken(t, tokens, "hash-b1"))
+	assert.Nil(t, findToken(t, tokens, "hash-c1").RevokedAt)
+	assert.NoError(t, tokens.RevokeUserRefreshTokens(ctx, 999, revokedAt))
+}
+
+func testRefreshTokenCanceledContext(t *testing.T, users repositories.UserRepository, tokens repositories.RefreshTokenRepository) {
+	user := create(t, users, "arthur")[0]
+	token := newRefreshToken(user.ID, "family", "hash-1", testTime)
+	require.NoError(t, tokens.CreateRefreshToken(context.Background(), token))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, tokens.CreateRefreshToken(ctx, newRefreshToken(user.ID, "family", "hash-2", testTime)), context.Canceled)
+	_, err := tokens.FindRefreshTokenByHash(ctx, "hash-1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, tokens.RotateRefreshToken(ctx, token.ID, newRefreshToken(user.ID, "family", "hash-3", testTime)), context.Canceled)
+	assert.ErrorIs(t, tokens.RevokeRefreshTokenFamily(ctx, "family", testTime), context.Canceled)
+	assert.ErrorIs(t, tokens.RevokeUserRefreshTokens(ctx, user.ID, testTime), context.Canceled)
+	assert.Nil(t, findToken(t, tokens, "hash-1").RevokedAt)
+}
@@ -0,0 +1,423 @@
+// Package repositorytest holds the conformance suites every
+// repositories.UserRepository and repositories.RefreshTokenRepository
+// implementation must pass.
+package repositorytest
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty repository. It is called once per subtest and
+// should register any cleanup on t.
+type Factory func(t *testing.T) repositories.UserRepository
+
+// Run checks the behavior shared by all backends. Usernames used in ordering
+// assertions are plain lowercase ASCII so the expectations hold under any
+// database collation.
+func Run(t *testing.T, newRepository Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo repositories.UserRepository)
+	}{
+		{"CreateUser", testCreateUser},
+		{"CreateUser_DuplicateUsername", testCreateUserDuplicate},
+		{"CreateUser_UsernameIsCaseSensitive", testCreateUserCaseSensitive},
+		{"IDsIncreaseAndAreNotReused", testIDsNotReused},
+		{"FindUserByID", testFindUserByID},
+		{"FindUserByID_NotFound", testFindUserByIDNotFound},
+		{"FindUserByUsername", testFindUserByUsername},
+		{"FindAllUsers_DefaultOrder", testFindAllDefaultOrder},
+		{"FindAllUsers_Paginated", testFindAllPaginated},
+		{"FindAllUsers_PrefixIsLiteral", testFindAllPrefixLiteral},
+		{"FindAllUsers_Sort", testFindAllSort},
+		{"FindAllUsers_Keyset", testFindAllKeyset},
+		{"FindAllUsers_InvalidSortField", testFindAllInvalidSort},
+		{"UpdateUser", testUpdateUser},
+		{"UpdateUser_DuplicateUsername", testUpdateUserDuplicate},
+		{"PatchUser", testPatchUser},
+		{"PasswordHash", testPasswordHash},
+		{"NotFound", testNotFound},
+		{"DeleteUser", testDeleteUser},
+		{"CanceledContext", testCanceledContext},
+		{"ConcurrentCreates", testConcurrentCreates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepository(t))
+		})
+	}
+}
+
+func create(t *testing.T, repo repositories.UserRepository, usernames ...string) []*model.User {
+	users := make([]*model.User, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := repo.CreateUser(context.Background(), &dto.UserRequest{Username: username, PasswordHash: hashOf(username)})
+		require.NoError(t, err, "create %s", username)
+		users = append(users, user)
+	}
+	return users
+}
+
+// hashOf stands in for a real password hash; repositories store it verbatim.
+func hashOf(username string) model.PasswordHash {
+	return model.PasswordHash("hash-" + username)
+}
+
+func usernames(users []*model.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
+
+func testCreateUser(t *testing.T, repo repositories.UserRepository) {
+	user, err := repo.CreateUser(context.Background(), &dto.UserRequest{Username: "arthur"})
+
+	require.NoError(t, err)
+	assert.NotZero(t, user.ID)
+	assert.Equal(t, "arthur", user.Username)
+}
+
+func testCreateUserDuplicate(t *testing.T, repo repositories.UserRepository) {
+	create(t, repo, "arthur")
+
+	user, err := repo.CreateUser(context.Background(), &dto.UserRequest{Username: "arthur"})
+
+	assert.ErrorIs(t, err, repositories.ErrUsernameAlreadyExists)
+	assert.Nil(t, user)
+}
+
+func testCreateUserCaseSensitive(t *testing.T, repo repositories.UserRepository) {
+	create(t, repo, "arthur")
+
+	_, err := repo.CreateUser(context.Background(), &dto.UserRequest{Username: "Arthur"})
+
+	assert.NoError(t, err)
+}
+
+func testIDsNotReused(t *testing.T, repo repositories.UserRepository) {
+	users := create(t, repo, "first", "second")
+	assert.Greater(t, users[1].ID, users[0].ID)
+
+	require.NoError(t, repo.DeleteUser(context.Background(), users[1].ID))
+	third := create(t, repo, "third")[0]
+
+	assert.Greater(t, third.ID, users[1].ID)
+}
+
+func testFindUserByID(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur")[0]
+
+	user, err := repo.FindUserByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, user)
+
+	user.Username = "mutated"
+	user, err = repo.FindUserByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "arthur", user.Username)
+}
+
+func testFindUserByIDNotFound(t *testing.T, repo repositories.UserRepository) {
+	user, err := repo.FindUserByID(context.Background(), 999)
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	assert.Nil(t, user)
+}
+
+func testFindUserByUsername(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur", "other")[0]
+	ctx := context.Background()
+
+	user, err := repo.FindUserByUsername(ctx, "arthur")
+	require.NoError(t, err)
+	assert.Equal(t, created, user)
+
+	for _, username := range []string{"Arthur", "arth", "arthur%", "missing"} {
+		user, err = repo.FindUserByUsername(ctx, username)
+		assert.ErrorIs(t, err, repositories.ErrUserNotFound, username)
+		assert.Nil(t, user, username)
+	}
+}
+
+func testFindAllDefaultOrder(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "carol", "alice", "bob")
+
+	users, total, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, created, users)
+}
+
+func testFindAllPaginated(t *testing.T, repo repositories.UserRepository) {
+	create(t, repo, "alice", "bob", "alex", "alan")
+	ctx := context.Background()
+
+	users, total, err := repo.FindAllUsers(ctx, &dto.UserFilter{
+		Limit:          2,
+		Offset:         1,
+		Sort:           []dto.SortField{{Field: "username", Desc: true}},
+		UsernamePrefix: "al",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []string{"alex", "alan"}, usernames(users))
+
+	users, total, err = repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10, Offset: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Empty(t, users)
+
+	users, total, err = repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10, UsernamePrefix: "zz"})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, users)
+}
+
+func testFindAllPrefixLiteral(t *testing.T, repo repositories.UserRepository) {
+	create(t, repo, "al_x", "alex", "al%y", "Alan")
+	ctx := context.Background()
+
+	for prefix, want := range map[string][]string{
+		"al_": {"al_x"},
+		"al%": {"al%y"},
+		"Al":  {"Alan"},
+	} {
+		users, total, err := repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10, UsernamePrefix: prefix})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(want)), total, prefix)
+		assert.Equal(t, want, usernames(users), prefix)
+	}
+}
+
+func testFindAllSort(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "bob", "alice", "carol")
+	ctx := context.Background()
+
+	users, _, err := repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10, Sort: []dto.SortField{{Field: "username"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "carol"}, usernames(users))
+
+	users, _, err = repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10, Sort: []dto.SortField{{Field: "id", Desc: true}}})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.User{created[2], created[1], created[0]}, users)
+}
+
+func testFindAllKeyset(t *testing.T, repo repositories.UserRepository) {
+	create(t, repo, "carol", "alice", "bob", "dave", "erin")
+
+	for _, sort := range [][]dto.SortField{
+		{{Field: "username", Desc: true}},
+		{{Field: "username"}},
+		{{Field: "id", Desc: true}},
+		nil,
+	} {
+		all, _, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{Limit: 10, Sort: sort})
+		require.NoError(t, err)
+
+		var seen []*model.User
+		var after *dto.UserCursor
+		for {
+			users, _, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{Limit: 2, Sort: sort, After: after})
+			require.NoError(t, err)
+			seen = append(seen, users...)
+			if len(users) < 2 {
+				break
+			}
+			last := users[len(users)-1]
+			after = &dto.UserCursor{ID: last.ID, Username: last.Username}
+		}
+
+		assert.Equal(t, all, seen, fmt.Sprint(sort))
+	}
+}
+
+func testFindAllInvalidSort(t *testing.T, repo repositories.UserRepository) {
+	users, _, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{
+		Limit: 10,
+		Sort:  []dto.SortField{{Field: "password"}},
+	})
+
+	assert.ErrorIs(t, err, repositories.ErrInvalidSortField)
+	assert.Nil(t, users)
+}
+
+func testUpdateUser(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur")[0]
+	ctx := context.Background()
+
+	user, err := repo.UpdateUser(ctx, created.ID, &dto.UserRequest{Username: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, user.ID)
+	assert.Equal(t, "renamed", user.Username)
+
+	user, err = repo.UpdateUser(ctx, created.ID, &dto.UserRequest{Username: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", user.Username)
+
+	user, err = repo.FindUserByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", user.Username)
+}
+
+func testUpdateUserDuplicate(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "first", "second")
+	ctx := context.Background()
+
+	user, err := repo.UpdateUser(ctx, created[1].ID, &dto.UserRequest{Username: "first"})
+	assert.ErrorIs(t, err, repositories.ErrUsernameAlreadyExists)
+	assert.Nil(t, user)
+
+	username := "first"
+	user, err = repo.PatchUser(ctx, created[1].ID, &dto.UserPatchRequest{Username: &username})
+	assert.ErrorIs(t, err, repositories.ErrUsernameAlreadyExists)
+	assert.Nil(t, user)
+
+	user, err = repo.FindUserByID(ctx, created[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "second", user.Username)
+}
+
+func testPatchUser(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur")[0]
+	ctx := context.Background()
+
+	user, err := repo.PatchUser(ctx, created.ID, &dto.UserPatchRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, created, user)
+
+	username := "patched"
+	user, err = repo.PatchUser(ctx, created.ID, &dto.UserPatchRequest{Username: &username})
+	require.NoError(t, err)
+	assert.Equal(t, "patched", user.Username)
+}
+
+func testPasswordHash(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur")[0]
+	ctx := context.Background()
+	assert.Equal(t, hashOf("arthur"), created.PasswordHash)
+
+	user, err := repo.FindUserByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, hashOf("arthur"), user.PasswordHash)
+
+	user, err = repo.UpdateUser(ctx, created.ID, &dto.UserRequest{Username: "arthur", PasswordHash: "replaced"})
+	require.NoError(t, err)
+	assert.Equal(t, model.PasswordHash("replaced"), user.PasswordHash)
+
+	username := "renamed"
+	user, err = repo.PatchUser(ctx, created.ID, &dto.UserPatchRequest{Username: &username})
+	require.NoError(t, err)
+	assert.Equal(t, model.PasswordHash("replaced"), user.PasswordHash, "a patch without a hash keeps the old one")
+
+	hash := model.PasswordHash("patched")
+	_, err = repo.PatchUser(ctx, created.ID, &dto.UserPatchRequest{PasswordHash: &hash})
+	require.NoError(t, err)
+	user, err = repo.FindUserByUsername(ctx, "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, hash, user.PasswordHash)
+}
+
+func testNotFound(t *testing.T, repo repositories.UserRepository) {
+	ctx := context.Background()
+	username := "patched"
+
+	_, err := repo.UpdateUser(ctx, 999, &dto.UserRequest{Username: "renamed"})
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	_, err = repo.PatchUser(ctx, 999, &dto.UserPatchRequest{})
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	_, err = repo.PatchUser(ctx, 999, &dto.UserPatchRequest{Username: &username})
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	assert.ErrorIs(t, repo.DeleteUser(ctx, 999), repositories.ErrUserNotFound)
+}
+
+func testDeleteUser(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur", "other")
+	ctx := context.Background()
+
+	require.NoError(t, repo.DeleteUser(ctx, created[0].ID))
+
+	_, err := repo.FindUserByID(ctx, created[0].ID)
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	assert.ErrorIs(t, repo.DeleteUser(ctx, created[0].ID), repositories.ErrUserNotFound)
+
+	users, total, err := repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"other"}, usernames(users))
+
+	_, err = repo.CreateUser(ctx, &dto.UserRequest{Username: "arthur"})
+	assert.NoError(t, err)
+}
+
+func testCanceledContext(t *testing.T, repo repositories.UserRepository) {
+	created := create(t, repo, "arthur")[0]
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.CreateUser(ctx, &dto.UserRequest{Username: "other"})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.FindUserByID(ctx, created.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.FindUserByUsername(ctx, created.Username)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, _, err = repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = repo.UpdateUser(ctx, created.ID, &dto.UserRequest{Username: "renamed"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, repo.DeleteUser(ctx, created.ID), context.Canceled)
+
+	users, _, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.User{created}, users)
+}
+
+func testConcurrentCreates(t *testing.T, repo repositories.UserRepository) {
+	const workers = 20
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		ids  = map[uint]bool{}
+		errs []error
+	)
+	for i := 0; i < workers; i++ {
+		username := fmt.Sprintf("user%02d", i)
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				user, err := repo.CreateUser(context.Background(), &dto.UserRequest{Username: username})
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+					return
+				}
+				ids[user.ID] = true
+			}()
+		}
+	}
+	wg.Wait()
+
+	assert.Len(t, ids, workers)
+	assert.Len(t, errs, workers)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, repositories.ErrUsernameAlreadyExists)
+	}
+
+	_, total, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers), total)
+}
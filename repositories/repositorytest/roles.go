@@ -0,0 +1,151 @@
+package repositorytest
+
+import (
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RoleFactory returns an empty user repository and a role repository backed
+// by the same store, holding the default role catalogue.
+type RoleFactory func(t *testing.T) (repositories.UserRepository, repositories.RoleRepository)
+
+// RunRoles checks the behavior shared by all role backends.
+func RunRoles(t *testing.T, newRepositories RoleFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository)
+	}{
+		{"FindAll_DefaultCatalogue", testFindAllRoles},
+		{"AssignAndFindByUser", testAssignRole},
+		{"Assign_Idempotent", testAssignRoleTwice},
+		{"Revoke", testRevokeRole},
+		{"Revoke_NotAssigned", testRevokeUnassignedRole},
+		{"UnknownUser", testRolesUnknownUser},
+		{"UnknownRole", testRolesUnknownRole},
+		{"CanceledContext", testRolesCanceledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, roles := newRepositories(t)
+			tt.run(t, users, roles)
+		})
+	}
+}
+
+func roleNames(roles []*model.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func userRoles(t *testing.T, roles repositories.RoleRepository, userID uint) []string {
+	found, err := roles.FindRolesByUserID(context.Background(), userID)
+	require.NoError(t, err, "find roles of user %d", userID)
+	return roleNames(found)
+}
+
+func testFindAllRoles(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	found, err := roles.FindAllRoles(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, found, len(authz.DefaultRoles))
+	assert.Equal(t, []string{authz.RoleAdmin, authz.RoleOperator, authz.RoleViewer}, roleNames(found))
+	for _, role := range found {
+		var definition authz.RoleDefinition
+		for _, candidate := range authz.DefaultRoles {
+			if candidate.Name == role.Name {
+				definition = candidate
+			}
+		}
+		permissions := make([]authz.Permission, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, authz.Permission(permission.Name))
+		}
+		assert.Equal(t, definition.Description, role.Description, role.Name)
+		assert.Equal(t, authz.PermissionNames(definition.Permissions), authz.PermissionNames(permissions), role.Name)
+		assert.IsNonDecreasing(t, authz.PermissionNames(permissions), role.Name)
+	}
+}
+
+func testAssignRole(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	created := create(t, users, "arthur", "ford")
+	ctx := context.Background()
+
+	assert.Empty(t, userRoles(t, roles, created[0].ID))
+	require.NoError(t, roles.AssignRole(ctx, created[0].ID, authz.RoleViewer))
+	require.NoError(t, roles.AssignRole(ctx, created[0].ID, authz.RoleAdmin))
+
+	found, err := roles.FindRolesByUserID(ctx, created[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{authz.RoleAdmin, authz.RoleViewer}, roleNames(found))
+	assert.Len(t, found[0].Permissions, len(authz.DefaultRoles[0].Permissions))
+	assert.Empty(t, userRoles(t, roles, created[1].ID))
+}
+
+func testAssignRoleTwice(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	user := create(t, users, "arthur")[0]
+
+	require.NoError(t, roles.AssignRole(context.Background(), user.ID, authz.RoleOperator))
+	require.NoError(t, roles.AssignRole(context.Background(), user.ID, authz.RoleOperator))
+
+	assert.Equal(t, []string{authz.RoleOperator}, userRoles(t, roles, user.ID))
+}
+
+func testRevokeRole(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	user := create(t, users, "arthur")[0]
+	ctx := context.Background()
+	require.NoError(t, roles.AssignRole(ctx, user.ID, authz.RoleOperator))
+	require.NoError(t, roles.AssignRole(ctx, user.ID, authz.RoleViewer))
+
+	require.NoError(t, roles.RevokeRole(ctx, user.ID, authz.RoleOperator))
+
+	assert.Equal(t, []string{authz.RoleViewer}, userRoles(t, roles, user.ID))
+}
+
+func testRevokeUnassignedRole(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	user := create(t, users, "arthur")[0]
+
+	assert.NoError(t, roles.RevokeRole(context.Background(), user.ID, authz.RoleAdmin))
+	assert.Empty(t, userRoles(t, roles, user.ID))
+}
+
+func testRolesUnknownUser(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	ctx := context.Background()
+
+	_, err := roles.FindRolesByUserID(ctx, 999)
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	assert.ErrorIs(t, roles.AssignRole(ctx, 999, authz.RoleAdmin), repositories.ErrUserNotFound)
+	assert.ErrorIs(t, roles.RevokeRole(ctx, 999, authz.RoleAdmin), repositories.ErrUserNotFound)
+}
+
+func testRolesUnknownRole(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	user := create(t, users, "arthur")[0]
+	ctx := context.Background()
+
+	assert.ErrorIs(t, roles.AssignRole(ctx, user.ID, "superuser"), repositories.ErrRoleNotFound)
+	assert.ErrorIs(t, roles.RevokeRole(ctx, user.ID, "superuser"), repositories.ErrRoleNotFound)
+	assert.Empty(t, userRoles(t, roles, user.ID))
+}
+
+func testRolesCanceledContext(t *testing.T, users repositories.UserRepository, roles repositories.RoleRepository) {
+	user := create(t, users, "arthur")[0]
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := roles.FindAllRoles(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = roles.FindRolesByUserID(ctx, user.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, roles.AssignRole(ctx, user.ID, authz.RoleAdmin), context.Canceled)
+	assert.ErrorIs(t, roles.RevokeRole(ctx, user.ID, authz.RoleAdmin), context.Canceled)
+	assert.Empty(t, userRoles(t, roles, user.ID))
+}
@@ -0,0 +1,197 @@
+package repositorytest
+
+import (
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SigningClientFactory returns empty repositories backed by the same store,
+// so that clients can reference the users created through the first one.
+type SigningClientFactory func(t *testing.T) (repositories.UserRepository, repositories.SigningClientRepository)
+
+// RunSigningClients checks the behavior shared by all signing client
+// backends.
+func RunSigningClients(t *testing.T, newRepositories SigningClientFactory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository)
+	}{
+		{"CreateAndFindByClientID", testCreateSigningClient},
+		{"Create_UnknownUser", testCreateSigningClientUnknownUser},
+		{"FindByClientID_NotFound", testFindSigningClientNotFound},
+		{"FindByUser", testFindSigningClientsByUser},
+		{"FindByUser_UnknownUser", testFindSigningClientsUnknownUser},
+		{"Revoke", testRevokeSigningClient},
+		{"Revoke_OtherUser", testRevokeOtherUsersSigningClient},
+		{"UseNonce", testUseNonce},
+		{"PurgeExpiredNonces", testPurgeExpiredNonces},
+		{"CanceledContext", testSigningClientCanceledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, clients := newRepositories(t)
+			tt.run(t, users, clients)
+		})
+	}
+}
+
+func newSigningClient(userID uint, name, clientID string) *model.SigningClient {
+	return &model.SigningClient{
+		Credential: newCredential(userID, name),
+		ClientID:   clientID,
+		Secret:     "secret-" + clientID,
+	}
+}
+
+func createSigningClient(t *testing.T, clients repositories.SigningClientRepository, client *model.SigningClient) *model.SigningClient {
+	require.NoError(t, clients.CreateSigningClient(context.Background(), client), "create %s", client.ClientID)
+	return client
+}
+
+func findSigningClient(t *testing.T, clients repositories.SigningClientRepository, clientID string) *model.SigningClient {
+	client, err := clients.FindSigningClientByClientID(context.Background(), clientID)
+	require.NoError(t, err, "find %s", clientID)
+	return client
+}
+
+func newNonce(clientID, nonce string, expiresAt time.Time) *model.SignatureNonce {
+	return &model.SignatureNonce{ClientID: clientID, Nonce: nonce, ExpiresAt: expiresAt}
+}
+
+func testCreateSigningClient(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	user := create(t, users, "arthur")[0]
+	client := createSigningClient(t, clients, newSigningClient(user.ID, "billing", "sc_1"))
+	assert.NotZero(t, client.ID)
+
+	found := findSigningClient(t, clients, "sc_1")
+	assert.Equal(t, client.ID, found.ID)
+	assert.Equal(t, user.ID, found.UserID)
+	assert.Equal(t, "billing", found.Name)
+	assert.Equal(t, "secret-sc_1", found.Secret)
+	assert.Equal(t, "users:read_all", found.Scopes)
+	assert.True(t, testTime.Equal(found.CreatedAt), "created at %s", found.CreatedAt)
+	assert.Nil(t, found.RevokedAt)
+}
+
+func testCreateSigningClientUnknownUser(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	err := clients.CreateSigningClient(context.Background(), newSigningClient(999, "billing", "sc_1"))
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+	_, err = clients.FindSigningClientByClientID(context.Background(), "sc_1")
+	assert.ErrorIs(t, err, repositories.ErrSigningClientNotFound)
+}
+
+func testFindSigningClientNotFound(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	client, err := clients.FindSigningClientByClientID(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, repositories.ErrSigningClientNotFound)
+	assert.Nil(t, client)
+}
+
+func testFindSigningClientsByUser(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	created := create(t, users, "arthur", "ford", "zaphod")
+	createSigningClient(t, clients, newSigningClient(created[0].ID, "first", "sc_1"))
+	createSigningClient(t, clients, newSigningClient(created[1].ID, "other", "sc_2"))
+	createSigningClient(t, clients, newSigningClient(created[0].ID, "second", "sc_3"))
+
+	found, err := clients.FindSigningClientsByUserID(context.Background(), created[0].ID)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "first", found[0].Name)
+	assert.Equal(t, "second", found[1].Name)
+
+	found, err = clients.FindSigningClientsByUserID(context.Background(), created[2].ID)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testFindSigningClientsUnknownUser(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	_, err := clients.FindSigningClientsByUserID(context.Background(), 999)
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+}
+
+func testRevokeSigningClient(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	user := create(t, users, "arthur")[0]
+	client := createSigningClient(t, clients, newSigningClient(user.ID, "billing", "sc_1"))
+	revokedAt := testTime.Add(time.Hour)
+
+	require.NoError(t, clients.RevokeSigningClient(context.Background(), user.ID, client.ID, revokedAt))
+	require.NoError(t, clients.RevokeSigningClient(context.Background(), user.ID, client.ID, revokedAt.Add(time.Hour)))
+
+	found := findSigningClient(t, clients, "sc_1")
+	if assert.NotNil(t, found.RevokedAt) {
+		assert.True(t, revokedAt.Equal(*found.RevokedAt), "revoked at %s", found.RevokedAt)
+	}
+}
+
+func testRevokeOtherUsersSigningClient(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	created := create(t, users, "arthur", "ford")
+	client := createSigningClient(t, clients, newSigningClient(created[0].ID, "billing", "sc_1"))
+
+	assert.ErrorIs(t, clients.RevokeSigningClient(context.Background(), created[1].ID, client.ID, testTime), repositories.ErrSigningClientNotFound)
+	assert.ErrorIs(t, clients.RevokeSigningClient(context.Background(), created[0].ID, 999, testTime), repositories.ErrSigningClientNotFound)
+	assert.Nil(t, findSigningClient(t, clients, "sc_1").RevokedAt)
+}
+
+func testUseNonce(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	ctx := context.Background()
+	expiresAt := testTime.Add(5 * time.Minute)
+
+	require.NoError(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-1", expiresAt)))
+	assert.ErrorIs(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-1", expiresAt.Add(time.Minute))), repositories.ErrNonceReused)
+	assert.NoError(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-2", expiresAt)))
+	assert.NoError(t, clients.UseNonce(ctx, newNonce("sc_2", "nonce-1", expiresAt)))
+}
+
+func testPurgeExpiredNonces(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	ctx := context.Background()
+	expiresAt := testTime.Add(5 * time.Minute)
+	for _, nonce := range []string{"nonce-1", "nonce-2", "nonce-3"} {
+		require.NoError(t, clients.UseNonce(ctx, newNonce("sc_1", nonce, expiresAt)))
+	}
+	require.NoError(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-4", expiresAt.Add(time.Second))))
+
+	purged, err := clients.PurgeExpiredNonces(ctx, expiresAt.Add(-time.Second), 10)
+	require.NoError(t, err)
+	assert.Zero(t, purged, "nothing has expired yet")
+	assert.ErrorIs(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-1", expiresAt)), repositories.ErrNonceReused, "expired but not purged")
+
+	purged, err = clients.PurgeExpiredNonces(ctx, expiresAt, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	purged, err = clients.PurgeExpiredNonces(ctx, expiresAt, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	for _, nonce := range []string{"nonce-1", "nonce-2", "nonce-3"} {
+		assert.NoError(t, clients.UseNonce(ctx, newNonce("sc_1", nonce, expiresAt.Add(time.Hour))), nonce)
+	}
+	assert.ErrorIs(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-4", expiresAt)), repositories.ErrNonceReused)
+}
+
+func testSigningClientCanceledContext(t *testing.T, users repositories.UserRepository, clients repositories.SigningClientRepository) {
+	user := create(t, users, "arthur")[0]
+	client := createSigningClient(t, clients, newSigningClient(user.ID, "billing", "sc_1"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, clients.CreateSigningClient(ctx, newSigningClient(user.ID, "billing", "sc_2")), context.Canceled)
+	_, err := clients.FindSigningClientByClientID(ctx, "sc_1")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = clients.FindSigningClientsByUserID(ctx, user.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, clients.RevokeSigningClient(ctx, user.ID, client.ID, testTime), context.Canceled)
+	assert.ErrorIs(t, clients.UseNonce(ctx, newNonce("sc_1", "nonce-1", testTime.Add(time.Minute))), context.Canceled)
+	_, err = clients.PurgeExpiredNonces(ctx, testTime, 10)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, findSigningClient(t, clients, "sc_1").RevokedAt)
+	assert.NoError(t, clients.UseNonce(context.Background(), newNonce("sc_1", "nonce-1", testTime.Add(time.Minute))))
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+)
+
+var ErrRoleNotFound = apperror.NotFound("role_not_found", "role not found")
+
+//go:generate go run go.uber.org/mock/mockgen -source=role_repository.go -destination=mocks/role_repository_mock.go -package=mocks
+
+// RoleRepository reads the role catalogue and the roles assigned to users.
+// Roles are returned by name, with their permissions.
+type RoleRepository interface {
+	FindAllRoles(ctx context.Context) ([]*model.Role, error)
+	// FindRolesByUserID fails with ErrUserNotFound for unknown users.
+	FindRolesByUserID(ctx context.Context, userID uint) ([]*model.Role, error)
+	// AssignRole and RevokeRole are idempotent. They fail with
+	// ErrUserNotFound or ErrRoleNotFound when either side does not exist.
+	AssignRole(ctx context.Context, userID uint, role string) error
+	RevokeRole(ctx context.Context, userID uint, role string) error
+}
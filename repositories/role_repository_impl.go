@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepositoryImpl{db: db}
+}
+
+func (r *roleRepositoryImpl) FindAllRoles(ctx context.Context) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := r.db.WithContext(ctx).Preload("Permissions", orderPermissions).Order("name").Find(&roles).Error
+	if err != nil {
+		return nil, translateRoleError(err)
+	}
+	return roles, nil
+}
+
+func (r *roleRepositoryImpl) FindRolesByUserID(ctx context.Context, userID uint) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := userExists(tx, userID); err != nil {
+			return err
+		}
+		return tx.Preload("Permissions", orderPermissions).
+			Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+			Where("user_roles.user_id = ?", userID).
+			Order("roles.name").
+			Find(&roles).Error
+	})
+	if err != nil {
+		return nil, translateRoleError(err)
+	}
+	return roles, nil
+}
+
+func (r *roleRepositoryImpl) AssignRole(ctx context.Context, userID uint, role string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		assignment, err := findAssignment(tx, userID, role)
+		if err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(assignment).Error
+	})
+	if err != nil {
+		return translateRoleError(err)
+	}
+	return nil
+}
+
+func (r *roleRepositoryImpl) RevokeRole(ctx context.Context, userID uint, role string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		assignment, err := findAssignment(tx, userID, role)
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND role_id = ?", assignment.UserID, assignment.RoleID).Delete(&model.UserRole{}).Error
+	})
+	if err != nil {
+		return translateRoleError(err)
+	}
+	return nil
+}
+
+func orderPermissions(db *gorm.DB) *gorm.DB {
+	return db.Order("permissions.name")
+}
+
+func userExists(tx *gorm.DB, userID uint) error {
+	var count int64
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// findAssignment resolves the user and role of an assignment, which may or
+// may not exist yet.
+func findAssignment(tx *gorm.DB, userID uint, role string) (*model.UserRole, error) {
+	var found model.Role
+	err := tx.Where("name = ?", role).First(&found).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := userExists(tx, userID); err != nil {
+		return nil, err
+	}
+	return &model.UserRole{UserID: userID, RoleID: found.ID}, nil
+}
+
+func translateRoleError(err error) error {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return apperror.Internal(err)
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"sort"
+	"sync"
+)
+
+// roleRepositoryMemory serves the catalogue in authz.DefaultRoles and keeps
+// assignments in a map guarded by a mutex. Users are looked up through the
+// user repository; unlike the database, assignments are not removed when the
+// user is deleted, which is harmless because memory user IDs are never reused.
+type roleRepositoryMemory struct {
+	users UserRepository
+
+	mu       sync.Mutex
+	roles    []model.Role
+	assigned map[uint]map[uint]struct{}
+}
+
+func NewMemoryRoleRepository(users UserRepository) RoleRepository {
+	var (
+		roles       []model.Role
+		permissions = map[authz.Permission]model.Permission{}
+	)
+	for _, definition := range authz.DefaultRoles {
+		role := model.Role{ID: uint(len(roles) + 1), Name: definition.Name, Description: definition.Description}
+		for _, name := range definition.Permissions {
+			permission, ok := permissions[name]
+			if !ok {
+				permission = model.Permission{ID: uint(len(permissions) + 1), Name: string(name)}
+				permissions[name] = permission
+			}
+			role.Permissions = append(role.Permissions, permission)
+		}
+		sort.Slice(role.Permissions, func(i, j int) bool {
+			return role.Permissions[i].Name < role.Permissions[j].Name
+		})
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	return &roleRepositoryMemory{users: users, roles: roles, assigned: map[uint]map[uint]struct{}{}}
+}
+
+func (r *roleRepositoryMemory) FindAllRoles(ctx context.Context) ([]*model.Role, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	return r.filter(func(model.Role) bool { return true }), nil
+}
+
+func (r *roleRepositoryMemory) FindRolesByUserID(ctx context.Context, userID uint) ([]*model.Role, error) {
+	if _, err := r.users.FindUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	assigned := r.assigned[userID]
+	roles := r.filter(func(role model.Role) bool {
+		_, ok := assigned[role.ID]
+		return ok
+	})
+	r.mu.Unlock()
+	return roles, nil
+}
+
+func (r *roleRepositoryMemory) AssignRole(ctx context.Context, userID uint, role string) error {
+	roleID, err := r.findAssignment(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.assigned[userID] == nil {
+		r.assigned[userID] = map[uint]struct{}{}
+	}
+	r.assigned[userID][roleID] = struct{}{}
+	return nil
+}
+
+func (r *roleRepositoryMemory) RevokeRole(ctx context.Context, userID uint, role string) error {
+	roleID, err := r.findAssignment(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.assigned[userID], roleID)
+	return nil
+}
+
+// findAssignment returns the ID of role once both it and the user exist.
+func (r *roleRepositoryMemory) findAssignment(ctx context.Context, userID uint, role string) (uint, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, apperror.Internal(err)
+	}
+	for _, candidate := range r.roles {
+		if candidate.Name != role {
+			continue
+		}
+		if _, err := r.users.FindUserByID(ctx, userID); err != nil {
+			return 0, err
+		}
+		return candidate.ID, nil
+	}
+	return 0, ErrRoleNotFound
+}
+
+// filter returns copies of the matching roles, so that callers cannot alter
+// the catalogue.
+func (r *roleRepositoryMemory) filter(keep func(model.Role) bool) []*model.Role {
+	roles := []*model.Role{}
+	for _, role := range r.roles {
+		if !keep(role) {
+			continue
+		}
+		role.Permissions = append([]model.Permission(nil), role.Permissions...)
+		roles = append(roles, &role)
+	}
+	return roles
+}
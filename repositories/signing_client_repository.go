@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"time"
+)
+
+var (
+	ErrSigningClientNotFound = apperror.NotFound("signing_client_not_found", "signing client not found")
+	ErrNonceReused           = apperror.Conflict("nonce_reused", "nonce has already been used")
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=signing_client_repository.go -destination=mocks/signing_client_repository_mock.go -package=mocks
+
+type SigningClientRepository interface {
+	// CreateSigningClient fails with ErrUserNotFound when the owner does not
+	// exist.
+	CreateSigningClient(ctx context.Context, client *model.SigningClient) error
+	FindSigningClientByClientID(ctx context.Context, clientID string) (*model.SigningClient, error)
+	// FindSigningClientsByUserID returns the clients of a user, revoked ones
+	// included, oldest first. It fails with ErrUserNotFound for unknown users.
+	FindSigningClientsByUserID(ctx context.Context, userID uint) ([]*model.SigningClient, error)
+	// RevokeSigningClient fails with ErrSigningClientNotFound unless the
+	// client belongs to userID. Revoking a revoked client keeps its original
+	// revocation time.
+	RevokeSigningClient(ctx context.Context, userID, id uint, at time.Time) error
+	// UseNonce records nonce, failing with ErrNonceReused if the client
+	// already used it. A nonce stays recorded, expired or not, until
+	// PurgeExpiredNonces removes it.
+	UseNonce(ctx context.Context, nonce *model.SignatureNonce) error
+	// PurgeExpiredNonces removes at most limit nonces that expired by now and
+	// returns how many it removed.
+	PurgeExpiredNonces(ctx context.Context, now time.Time, limit int) (int64, error)
+}
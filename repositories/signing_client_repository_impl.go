@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type signingClientRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSigningClientRepository(db *gorm.DB) SigningClientRepository {
+	return &signingClientRepositoryImpl{db: db}
+}
+
+func (r *signingClientRepositoryImpl) CreateSigningClient(ctx context.Context, client *model.SigningClient) error {
+	if err := createCredential(r.db.WithContext(ctx), client.UserID, client); err != nil {
+		return translateSigningClientError(err)
+	}
+	return nil
+}
+
+func (r *signingClientRepositoryImpl) FindSigningClientByClientID(ctx context.Context, clientID string) (*model.SigningClient, error) {
+	var client model.SigningClient
+	err := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error
+	if err != nil {
+		return nil, translateSigningClientError(err)
+	}
+	return &client, nil
+}
+
+func (r *signingClientRepositoryImpl) FindSigningClientsByUserID(ctx context.Context, userID uint) ([]*model.SigningClient, error) {
+	clients, err := findCredentialsByUserID[model.SigningClient](r.db.WithContext(ctx), userID)
+	if err != nil {
+		return nil, translateSigningClientError(err)
+	}
+	return clients, nil
+}
+
+func (r *signingClientRepositoryImpl) RevokeSigningClient(ctx context.Context, userID, id uint, at time.Time) error {
+	if err := revokeCredential[model.SigningClient](r.db.WithContext(ctx), userID, id, at); err != nil {
+		return translateSigningClientError(err)
+	}
+	return nil
+}
+
+func (r *signingClientRepositoryImpl) UseNonce(ctx context.Context, nonce *model.SignatureNonce) error {
+	// The primary key detects the reuse: a single insert, without locking
+	// anything but the new row.
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(nonce)
+	if result.Error != nil {
+		return translateSigningClientError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNonceReused
+	}
+	return nil
+}
+
+func (r *signingClientRepositoryImpl) PurgeExpiredNonces(ctx context.Context, now time.Time, limit int) (int64, error) {
+	db := r.db.WithContext(ctx)
+	// DELETE has no LIMIT in PostgreSQL, so the batch is picked by a
+	// subquery.
+	expired := db.Model(&model.SignatureNonce{}).
+		Select("client_id, nonce").
+		Where("expires_at <= ?", now).
+		Limit(limit)
+	result := db.Where("(client_id, nonce) IN (?)", expired).Delete(&model.SignatureNonce{})
+	if result.Error != nil {
+		return 0, translateSigningClientError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func translateSigningClientError(err error) error {
+	return translateCredentialError(err, ErrSigningClientNotFound)
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"sync"
+	"time"
+)
+
+type nonceKey struct {
+	clientID string
+	nonce    string
+}
+
+// signingClientRepositoryMemory mirrors signingClientRepositoryImpl on a
+// credentialStore, with the nonces in a map guarded by a mutex.
+type signingClientRepositoryMemory struct {
+	clients *credentialStore[model.SigningClient, *model.SigningClient]
+
+	mu     sync.Mutex
+	nonces map[nonceKey]time.Time
+}
+
+func NewMemorySigningClientRepository(users UserRepository) SigningClientRepository {
+	return &signingClientRepositoryMemory{
+		clients: newCredentialStore[model.SigningClient](users, ErrSigningClientNotFound),
+		nonces:  map[nonceKey]time.Time{},
+	}
+}
+
+func (r *signingClientRepositoryMemory) CreateSigningClient(ctx context.Context, client *model.SigningClient) error {
+	return r.clients.create(ctx, client, func(existing *model.SigningClient) bool {
+		return existing.ClientID == client.ClientID
+	})
+}
+
+func (r *signingClientRepositoryMemory) FindSigningClientByClientID(ctx context.Context, clientID string) (*model.SigningClient, error) {
+	return r.clients.find(ctx, func(client *model.SigningClient) bool {
+		return client.ClientID == clientID
+	})
+}
+
+func (r *signingClientRepositoryMemory) FindSigningClientsByUserID(ctx context.Context, userID uint) ([]*model.SigningClient, error) {
+	return r.clients.findByUserID(ctx, userID)
+}
+
+func (r *signingClientRepositoryMemory) RevokeSigningClient(ctx context.Context, userID, id uint, at time.Time) error {
+	return r.clients.revoke(ctx, userID, id, at)
+}
+
+func (r *signingClientRepositoryMemory) UseNonce(ctx context.Context, nonce *model.SignatureNonce) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := nonceKey{clientID: nonce.ClientID, nonce: nonce.Nonce}
+	if _, ok := r.nonces[key]; ok {
+		return ErrNonceReused
+	}
+	r.nonces[key] = nonce.ExpiresAt
+	return nil
+}
+
+func (r *signingClientRepositoryMemory) PurgeExpiredNonces(ctx context.Context, now time.Time, limit int) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for key, expiresAt := range r.nonces {
+		if purged == int64(limit) {
+			break
+		}
+		if !expiresAt.After(now) {
+			delete(r.nonces, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -1,13 +1,27 @@
 package repositories
 
 import (
+	"Learn_Jenkins/domain/apperror"
 	"Learn_Jenkins/domain/dto"
 	"Learn_Jenkins/domain/model"
 	"context"
 )
 
+var (
+	ErrUserNotFound          = apperror.NotFound("user_not_found", "user not found")
+	ErrUsernameAlreadyExists = apperror.Conflict("username_taken", "username already exists")
+	ErrInvalidSortField      = apperror.Validation("invalid_sort_field", "invalid sort field")
+)
+
+//go:generate go run go.uber.org/mock/mockgen -source=user_repository.go -destination=mocks/user_repository_mock.go -package=mocks
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, req *dto.UserRequest) (*model.User, error)
 	FindUserByID(ctx context.Context, id uint) (*model.User, error)
-	FindAllUsers(ctx context.Context) ([]*model.User, error)
+	// FindUserByUsername matches the username exactly, case included.
+	FindUserByUsername(ctx context.Context, username string) (*model.User, error)
+	FindAllUsers(ctx context.Context, filter *dto.UserFilter) ([]*model.User, int64, error)
+	UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*model.User, error)
+	PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*model.User, error)
+	DeleteUser(ctx context.Context, id uint) error
 }
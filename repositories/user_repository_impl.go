@@ -1,9 +1,13 @@
 package repositories
 
 import (
+	"Learn_Jenkins/domain/apperror"
 	"Learn_Jenkins/domain/dto"
 	"Learn_Jenkins/domain/model"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -18,12 +22,13 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 
 func (r *userRepositoryImpl) CreateUser(ctx context.Context, req *dto.UserRequest) (*model.User, error) {
 	user := model.User{
-		Username: req.Username,
+		Username:     req.Username,
+		PasswordHash: req.PasswordHash,
 	}
 
 	err := r.db.WithContext(ctx).Create(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	return &user, nil
@@ -33,16 +38,180 @@ func (r *userRepositoryImpl) FindUserByID(ctx context.Context, id uint) (*model.
 	var user model.User
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
 
-func (r *userRepositoryImpl) FindAllUsers(ctx context.Context) ([]*model.User, error) {
+func (r *userRepositoryImpl) FindUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+var userSortColumns = map[string]string{
+	"id":       "id",
+	"username": "username",
+}
+
+func (r *userRepositoryImpl) FindAllUsers(ctx context.Context, filter *dto.UserFilter) ([]*model.User, int64, error) {
+	sort, err := userSortFields(filter.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	err = r.db.WithContext(ctx).Model(&model.User{}).Scopes(userFilterScope(filter)).Count(&total).Error
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+
+	query := r.db.WithContext(ctx).Scopes(userFilterScope(filter))
+	if filter.After != nil {
+		condition, args := userKeysetCondition(sort, filter.After)
+		query = query.Where(condition, args...)
+	}
+
 	var users []*model.User
-	err := r.db.WithContext(ctx).Find(&users).Error
+	err = query.
+		Order(userOrderBy(sort)).
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+	return users, total, nil
+}
+
+func (r *userRepositoryImpl) UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*model.User, error) {
+	return r.updateUser(ctx, id, map[string]interface{}{"username": req.Username, "password_hash": req.PasswordHash})
+}
+
+func (r *userRepositoryImpl) PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*model.User, error) {
+	updates := map[string]interface{}{}
+	if req.Username != nil {
+		updates["username"] = *req.Username
+	}
+	if req.PasswordHash != nil {
+		updates["password_hash"] = *req.PasswordHash
+	}
+	return r.updateUser(ctx, id, updates)
+}
+
+func (r *userRepositoryImpl) DeleteUser(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.User{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepositoryImpl) updateUser(ctx context.Context, id uint, updates map[string]interface{}) (*model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&user).Error; err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&user).Updates(updates).Error
+	})
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func userFilterScope(filter *dto.UserFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.UsernamePrefix != "" {
+			db = db.Where("username LIKE ? ESCAPE '\\'", escapeLike(filter.UsernamePrefix)+"%")
+		}
+		return db
+	}
+}
+
+func userSortFields(sort []dto.SortField) ([]dto.SortField, error) {
+	fields := make([]dto.SortField, 0, len(sort)+1)
+	hasID := false
+	for _, field := range sort {
+		column, ok := userSortColumns[field.Field]
+		if !ok {
+			return nil, apperror.Validation(ErrInvalidSortField.Code, fmt.Sprintf("unsupported sort field %q", field.Field))
+		}
+		if column == "id" {
+			hasID = true
+		}
+		fields = append(fields, dto.SortField{Field: column, Desc: field.Desc})
+		if hasID {
+			break
+		}
+	}
+	if !hasID {
+		fields = append(fields, dto.SortField{Field: "id"})
+	}
+	return fields, nil
+}
+
+func userOrderBy(sort []dto.SortField) string {
+	clauses := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			clauses = append(clauses, field.Field+" DESC")
+		} else {
+			clauses = append(clauses, field.Field)
+		}
+	}
+	return strings.Join(clauses, ", ")
+}
+
+func userKeysetCondition(sort []dto.SortField, after *dto.UserCursor) (string, []interface{}) {
+	values := map[string]interface{}{
+		"id":       after.ID,
+		"username": after.Username,
+	}
+
+	var (
+		disjunctions []string
+		args         []interface{}
+	)
+	for i, field := range sort {
+		conjunctions := make([]string, 0, i+1)
+		for _, previous := range sort[:i] {
+			conjunctions = append(conjunctions, previous.Field+" = ?")
+			args = append(args, values[previous.Field])
+		}
+		operator := " > ?"
+		if field.Desc {
+			operator = " < ?"
+		}
+		conjunctions = append(conjunctions, field.Field+operator)
+		args = append(args, values[field.Field])
+		disjunctions = append(disjunctions, "("+strings.Join(conjunctions, " AND ")+")")
+	}
+	return "(" + strings.Join(disjunctions, " OR ") + ")", args
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrUsernameAlreadyExists
+	default:
+		return apperror.Internal(err)
 	}
-	return users, nil
 }
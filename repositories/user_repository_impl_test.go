@@ -4,6 +4,7 @@ import (
 	"Learn_Jenkins/config"
 	"Learn_Jenkins/domain/dto"
 	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/migrations"
 	"context"
 	"fmt"
 	"os"
@@ -13,30 +14,55 @@ import (
 	"gorm.io/gorm"
 )
 
+var testConfig *config.Config
+
 func setupTestDB(t *testing.T) *gorm.DB {
-	db, err := config.InitTestDatabase()
+	db, err := config.InitTestDatabase(testConfig.Database)
 	if err != nil {
 		t.Fatalf("failed to connect to test database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.User{}); err != nil {
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	migrator, err := migrations.New(sqlDB, testConfig.Database.Driver)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
 		t.Fatalf("failed to migrate: %v", err)
 	}
 
-	if err := db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE").Error; err != nil {
-		t.Fatalf("failed to truncate users: %v", err)
+	if testConfig.Database.Driver == config.DriverPostgres {
+		if err := db.Exec("TRUNCATE TABLE users, signature_nonces RESTART IDENTITY CASCADE").Error; err != nil {
+			t.Fatalf("failed to truncate users: %v", err)
+		}
 	}
 
 	t.Cleanup(func() {
-		sqlDB, _ := db.DB()
 		_ = sqlDB.Close()
 	})
 
 	return db
 }
 
+// TestMain runs against a fresh in-memory SQLite database per test unless
+// TEST_DB_DRIVER=postgres, in which case DB_NAME_TESTING from ../.env is used.
 func TestMain(m *testing.M) {
-	err := config.CreateTestDatabase()
+	if os.Getenv("TEST_DB_DRIVER") != config.DriverPostgres {
+		testConfig = &config.Config{Database: config.DatabaseConfig{Driver: config.DriverSQLite}}
+		os.Exit(m.Run())
+	}
+
+	var err error
+	testConfig, err = config.Load("", "../.env")
+	if err != nil {
+		fmt.Println(err)
+		panic(err)
+	}
+
+	err = config.CreateTestDatabase(testConfig.Database)
 	if err != nil {
 		fmt.Println(err)
 		panic(err)
@@ -44,7 +70,7 @@ func TestMain(m *testing.M) {
 
 	code := m.Run()
 
-	// dropErr := config.DropTestDatabase()
+	// dropErr := config.DropTestDatabase(testConfig.Database)
 	// if dropErr != nil {
 	// 	panic(dropErr)
 	// }
@@ -88,10 +114,180 @@ func TestUserRepository_FindAllUsers(t *testing.T) {
 	db.Create(&model.User{Username: "User1"})
 	db.Create(&model.User{Username: "User2"})
 
-	users, err := repo.FindAllUsers(ctx)
+	users, total, err := repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10})
 
 	assert.NoError(t, err)
 	assert.Len(t, users, 2)
+	assert.Equal(t, int64(2), total)
 	assert.Equal(t, "User1", users[0].Username)
 	assert.Equal(t, "User2", users[1].Username)
 }
+
+func TestUserRepository_FindAllUsers_Paginated(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "alice"})
+	db.Create(&model.User{Username: "bob"})
+	db.Create(&model.User{Username: "alex"})
+	db.Create(&model.User{Username: "alan"})
+
+	users, total, err := repo.FindAllUsers(ctx, &dto.UserFilter{
+		Limit:          2,
+		Offset:         1,
+		Sort:           []dto.SortField{{Field: "username", Desc: true}},
+		UsernamePrefix: "al",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "alex", users[0].Username)
+	assert.Equal(t, "alan", users[1].Username)
+
+	db.Create(&model.User{Username: "al_x"})
+	users, total, err = repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 10, UsernamePrefix: "al_"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "al_x", users[0].Username)
+}
+
+func TestUserRepository_FindAllUsers_Keyset(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "carol"})
+	db.Create(&model.User{Username: "alice"})
+	db.Create(&model.User{Username: "bob"})
+	db.Create(&model.User{Username: "dave"})
+
+	sort := []dto.SortField{{Field: "username", Desc: true}}
+	var seen []string
+	var after *dto.UserCursor
+	for {
+		users, _, err := repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 3, Sort: sort, After: after})
+		assert.NoError(t, err)
+		for _, user := range users {
+			seen = append(seen, user.Username)
+		}
+		if len(users) < 3 {
+			break
+		}
+		last := users[len(users)-1]
+		after = &dto.UserCursor{ID: last.ID, Username: last.Username}
+	}
+
+	assert.Equal(t, []string{"dave", "carol", "bob", "alice"}, seen)
+}
+
+func TestUserRepository_FindAllUsers_InvalidSortField(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	users, _, err := repo.FindAllUsers(context.Background(), &dto.UserFilter{
+		Limit: 10,
+		Sort:  []dto.SortField{{Field: "password"}},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidSortField)
+	assert.Nil(t, users)
+}
+
+func TestUserRepository_FindUserByID_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user, err := repo.FindUserByID(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, user)
+}
+
+func TestUserRepository_CreateUser_DuplicateUsername(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "Arthur"})
+
+	user, err := repo.CreateUser(ctx, &dto.UserRequest{Username: "Arthur"})
+
+	assert.ErrorIs(t, err, ErrUsernameAlreadyExists)
+	assert.Nil(t, user)
+}
+
+func TestUserRepository_UpdateUser(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "TestUser"})
+
+	user, err := repo.UpdateUser(ctx, 1, &dto.UserRequest{Username: "Renamed"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+	assert.Equal(t, "Renamed", user.Username)
+}
+
+func TestUserRepository_UpdateUser_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user, err := repo.UpdateUser(context.Background(), 99, &dto.UserRequest{Username: "Renamed"})
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, user)
+}
+
+func TestUserRepository_UpdateUser_DuplicateUsername(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "User1"})
+	db.Create(&model.User{Username: "User2"})
+
+	user, err := repo.UpdateUser(ctx, 2, &dto.UserRequest{Username: "User1"})
+
+	assert.ErrorIs(t, err, ErrUsernameAlreadyExists)
+	assert.Nil(t, user)
+}
+
+func TestUserRepository_PatchUser(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "TestUser"})
+
+	user, err := repo.PatchUser(ctx, 1, &dto.UserPatchRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "TestUser", user.Username)
+
+	username := "Patched"
+	user, err = repo.PatchUser(ctx, 1, &dto.UserPatchRequest{Username: &username})
+	assert.NoError(t, err)
+	assert.Equal(t, "Patched", user.Username)
+}
+
+func TestUserRepository_DeleteUser(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	ctx := context.Background()
+	db.Create(&model.User{Username: "TestUser"})
+
+	err := repo.DeleteUser(ctx, 1)
+	assert.NoError(t, err)
+
+	_, err = repo.FindUserByID(ctx, 1)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	err = repo.DeleteUser(ctx, 1)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
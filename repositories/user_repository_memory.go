@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// userRepositoryMemory keeps users in a map guarded by a mutex. It mirrors
+// userRepositoryImpl, except that usernames are ordered byte-wise rather than
+// by the database collation.
+type userRepositoryMemory struct {
+	mu     sync.RWMutex
+	nextID uint
+	users  map[uint]model.User
+}
+
+func NewMemoryUserRepository() UserRepository {
+	return &userRepositoryMemory{users: map[uint]model.User{}}
+}
+
+func (r *userRepositoryMemory) CreateUser(ctx context.Context, req *dto.UserRequest) (*model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usernameTaken(req.Username, 0) {
+		return nil, ErrUsernameAlreadyExists
+	}
+
+	// IDs are never reused, like a database sequence.
+	r.nextID++
+	user := model.User{ID: r.nextID, Username: req.Username, PasswordHash: req.PasswordHash}
+	r.users[user.ID] = user
+	return &user, nil
+}
+
+func (r *userRepositoryMemory) FindUserByID(ctx context.Context, id uint) (*model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *userRepositoryMemory) FindUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *userRepositoryMemory) FindAllUsers(ctx context.Context, filter *dto.UserFilter) ([]*model.User, int64, error) {
+	fields, err := userSortFields(filter.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, apperror.Internal(err)
+	}
+
+	r.mu.RLock()
+	matched := make([]model.User, 0, len(r.users))
+	for _, user := range r.users {
+		if strings.HasPrefix(user.Username, filter.UsernamePrefix) {
+			matched = append(matched, user)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return compareUsers(fields, &matched[i], &matched[j]) < 0
+	})
+	total := int64(len(matched))
+
+	if filter.After != nil {
+		after := &model.User{ID: filter.After.ID, Username: filter.After.Username}
+		start := sort.Search(len(matched), func(i int) bool {
+			return compareUsers(fields, &matched[i], after) > 0
+		})
+		matched = matched[start:]
+	}
+
+	offset := min(max(filter.Offset, 0), len(matched))
+	matched = matched[offset:]
+	if filter.Limit >= 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	users := make([]*model.User, 0, len(matched))
+	for i := range matched {
+		users = append(users, &matched[i])
+	}
+	return users, total, nil
+}
+
+func (r *userRepositoryMemory) UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*model.User, error) {
+	return r.updateUser(ctx, id, &req.Username, &req.PasswordHash)
+}
+
+func (r *userRepositoryMemory) PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*model.User, error) {
+	return r.updateUser(ctx, id, req.Username, req.PasswordHash)
+}
+
+func (r *userRepositoryMemory) DeleteUser(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *userRepositoryMemory) updateUser(ctx context.Context, id uint, username *string, passwordHash *model.PasswordHash) (*model.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	if username != nil {
+		if r.usernameTaken(*username, id) {
+			return nil, ErrUsernameAlreadyExists
+		}
+		user.Username = *username
+	}
+	if passwordHash != nil {
+		user.PasswordHash = *passwordHash
+	}
+	r.users[id] = user
+	return &user, nil
+}
+
+func (r *userRepositoryMemory) usernameTaken(username string, exceptID uint) bool {
+	for id, user := range r.users {
+		if id != exceptID && user.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func compareUsers(fields []dto.SortField, a, b *model.User) int {
+	for _, field := range fields {
+		var result int
+		switch field.Field {
+		case "id":
+			switch {
+			case a.ID < b.ID:
+				result = -1
+			case a.ID > b.ID:
+				result = 1
+			}
+		case "username":
+			result = strings.Compare(a.Username, b.Username)
+		}
+		if field.Desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
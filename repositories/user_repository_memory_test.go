@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"Learn_Jenkins/domain/dto"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUserRepository_IDsAreSequential(t *testing.T) {
+	repo := NewMemoryUserRepository()
+	ctx := context.Background()
+
+	for i, username := range []string{"carol", "alice", "bob"} {
+		user, err := repo.CreateUser(ctx, &dto.UserRequest{Username: username})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(i+1), user.ID)
+	}
+
+	_, err := repo.CreateUser(ctx, &dto.UserRequest{Username: "alice"})
+	assert.ErrorIs(t, err, ErrUsernameAlreadyExists)
+	user, err := repo.CreateUser(ctx, &dto.UserRequest{Username: "dave"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), user.ID)
+}
+
+func TestMemoryUserRepository_FindAllUsers_NegativeLimitIsUnlimited(t *testing.T) {
+	repo := NewMemoryUserRepository()
+	ctx := context.Background()
+	for _, username := range []string{"carol", "alice", "bob"} {
+		_, err := repo.CreateUser(ctx, &dto.UserRequest{Username: username})
+		assert.NoError(t, err)
+	}
+
+	users, total, err := repo.FindAllUsers(ctx, &dto.UserFilter{Limit: -1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, users, 2)
+
+	users, _, err = repo.FindAllUsers(ctx, &dto.UserFilter{Limit: 0})
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
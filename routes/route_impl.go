@@ -2,22 +2,54 @@ package routes
 
 import (
 	"Learn_Jenkins/controllers"
+	"Learn_Jenkins/domain/authz"
 
 	"github.com/gin-gonic/gin"
 )
 
 type routeImpl struct {
-	Controller controllers.UserController
-	Router     *gin.Engine
+	Controller              controllers.UserController
+	HealthController        controllers.HealthController
+	AuthController          controllers.AuthController
+	RoleController          controllers.RoleController
+	APIKeyController        controllers.APIKeyController
+	SigningClientController controllers.SigningClientController
+	Authenticate            gin.HandlerFunc
+	// Authorize builds the middleware that checks the caller may perform an
+	// action; it runs after Authenticate.
+	Authorize func(authz.Action) gin.HandlerFunc
+	Router    *gin.Engine
 }
 
-func NewRoute(controller controllers.UserController, router *gin.Engine) UserService {
-	return &routeImpl{Controller: controller, Router: router}
+func NewRoute(controller controllers.UserController, healthController controllers.HealthController, authController controllers.AuthController, roleController controllers.RoleController, apiKeyController controllers.APIKeyController, signingClientController controllers.SigningClientController, authenticate gin.HandlerFunc, authorize func(authz.Action) gin.HandlerFunc, router *gin.Engine) UserService {
+	return &routeImpl{Controller: controller, HealthController: healthController, AuthController: authController, RoleController: roleController, APIKeyController: apiKeyController, SigningClientController: signingClientController, Authenticate: authenticate, Authorize: authorize, Router: router}
 }
 
 func (r *routeImpl) Run() {
-	r.Router.POST("/users", r.Controller.CreateUser)
-	r.Router.GET("/users/:id", r.Controller.FindUserByID)
-	r.Router.GET("/users", r.Controller.FindAllUsers)
+	r.Router.GET("/healthz", r.HealthController.Liveness)
+	r.Router.GET("/readyz", r.HealthController.Readiness)
+
+	r.Router.POST("/auth/login", r.AuthController.Login)
+	r.Router.POST("/auth/refresh", r.AuthController.Refresh)
+	r.Router.POST("/auth/logout", r.AuthController.Logout)
+
+	users := r.Router.Group("/users", r.Authenticate)
+	users.POST("", r.Authorize(authz.ActionCreateUser), r.Controller.CreateUser)
+	users.GET("/:id", r.Authorize(authz.ActionReadUser), r.Controller.FindUserByID)
+	users.GET("", r.Authorize(authz.ActionListUsers), r.Controller.FindAllUsers)
+	users.PUT("/:id", r.Authorize(authz.ActionUpdateUser), r.Controller.UpdateUser)
+	users.PATCH("/:id", r.Authorize(authz.ActionUpdateUser), r.Controller.PatchUser)
+	users.DELETE("/:id", r.Authorize(authz.ActionDeleteUser), r.Controller.DeleteUser)
+	users.GET("/:id/roles", r.Authorize(authz.ActionReadUserRoles), r.RoleController.FindUserRoles)
+	users.PUT("/:id/roles/:role", r.Authorize(authz.ActionManageUserRole), r.RoleController.AssignRole)
+	users.DELETE("/:id/roles/:role", r.Authorize(authz.ActionManageUserRole), r.RoleController.RevokeRole)
+	users.POST("/:id/api-keys", r.Authorize(authz.ActionManageAPIKeys), r.APIKeyController.CreateAPIKey)
+	users.GET("/:id/api-keys", r.Authorize(authz.ActionManageAPIKeys), r.APIKeyController.FindAPIKeys)
+	users.DELETE("/:id/api-keys/:key_id", r.Authorize(authz.ActionManageAPIKeys), r.APIKeyController.RevokeAPIKey)
+	users.POST("/:id/signing-clients", r.Authorize(authz.ActionManageSigningClients), r.SigningClientController.CreateSigningClient)
+	users.GET("/:id/signing-clients", r.Authorize(authz.ActionManageSigningClients), r.SigningClientController.FindSigningClients)
+	users.DELETE("/:id/signing-clients/:signing_client_id", r.Authorize(authz.ActionManageSigningClients), r.SigningClientController.RevokeSigningClient)
+
+	r.Router.GET("/roles", r.Authenticate, r.Authorize(authz.ActionListRoles), r.RoleController.FindAllRoles)
 
 }
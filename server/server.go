@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type ShutdownHook func(ctx context.Context) error
+
+type Server struct {
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	hooks           []ShutdownHook
+	ready           atomic.Bool
+}
+
+func New(addr string, handler http.Handler, shutdownTimeout, drainDelay time.Duration) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		shutdownTimeout: shutdownTimeout,
+		drainDelay:      drainDelay,
+	}
+}
+
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
+func (s *Server) OnShutdown(hook ShutdownHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+	s.ready.Store(true)
+	slog.Info("http server started", "addr", listener.Addr().String())
+
+	select {
+	case err := <-serveErr:
+		s.ready.Store(false)
+		return errors.Join(err, s.runHooks())
+	case <-ctx.Done():
+	}
+
+	s.ready.Store(false)
+	slog.Info("shutdown requested, draining connections", "drain_delay", s.drainDelay, "timeout", s.shutdownTimeout)
+	if s.drainDelay > 0 {
+		time.Sleep(s.drainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	err := s.httpServer.Shutdown(shutdownCtx)
+	if err != nil {
+		slog.Error("graceful shutdown did not complete, closing remaining connections", "error", err)
+		_ = s.httpServer.Close()
+	}
+	if serveErr := <-serveErr; !errors.Is(serveErr, http.ErrServerClosed) {
+		err = errors.Join(err, serveErr)
+	}
+	err = errors.Join(err, s.runHooks())
+	slog.Info("http server stopped")
+	return err
+}
+
+// runHooks gives the hooks a shutdown timeout of their own, so that they
+// still get to close and flush when draining used up all of Shutdown's.
+func (s *Server) runHooks() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, hook := range s.hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := New(listener.Addr().String(), handler, 5*time.Second, 0)
+	hookCalled := make(chan struct{})
+	srv.OnShutdown(func(ctx context.Context) error {
+		close(hookCalled)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, listener)
+	}()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+
+	<-started
+	assert.True(t, srv.Ready())
+	cancel()
+
+	assert.Eventually(t, func() bool { return !srv.Ready() }, time.Second, 10*time.Millisecond)
+	select {
+	case <-hookCalled:
+		t.Fatal("shutdown hooks ran before in-flight requests finished")
+	default:
+	}
+
+	close(release)
+	assert.Equal(t, "done", <-body)
+	assert.NoError(t, <-done)
+	<-hookCalled
+}
+
+func TestServer_ShutdownDeadlineExceeded(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := New(listener.Addr().String(), handler, 50*time.Millisecond, 0)
+	hookErr := errors.New("close failed")
+	var hookCtxErr error
+	hookDeadline := false
+	srv.OnShutdown(func(ctx context.Context) error {
+		_, hookDeadline = ctx.Deadline()
+		hookCtxErr = ctx.Err()
+		return hookErr
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, listener)
+	}()
+
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	err = <-done
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, hookErr)
+	assert.NoError(t, hookCtxErr, "hooks get a fresh deadline after Shutdown times out")
+	assert.True(t, hookDeadline, "hooks are still bounded")
+}
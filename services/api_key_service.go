@@ -0,0 +1,22 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"context"
+)
+
+// ErrInvalidAPIKey does not say whether the key is unknown, expired or
+// revoked.
+var ErrInvalidAPIKey = apperror.Unauthorized("invalid_api_key", "API key is invalid, expired or revoked")
+
+type APIKeyService interface {
+	// CreateAPIKey returns the new key; it is not stored and cannot be shown
+	// again.
+	CreateAPIKey(ctx context.Context, userID uint, req *dto.APIKeyRequest) (*dto.APIKeyCreatedResponse, error)
+	FindAPIKeys(ctx context.Context, userID uint) (*dto.APIKeyListResponse, error)
+	RevokeAPIKey(ctx context.Context, userID, id uint) error
+	// VerifyAPIKey records when the key was last used, to the minute.
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+}
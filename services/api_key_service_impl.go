@@ -0,0 +1,95 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/apikey"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/repositories"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// lastUsedPrecision bounds how often a busy key is written back to storage.
+const lastUsedPrecision = time.Minute
+
+type apiKeyServiceImpl struct {
+	apiKeys repositories.APIKeyRepository
+	now     func() time.Time
+}
+
+func NewAPIKeyService(apiKeys repositories.APIKeyRepository) APIKeyService {
+	return &apiKeyServiceImpl{apiKeys: apiKeys, now: time.Now}
+}
+
+func (s *apiKeyServiceImpl) CreateAPIKey(ctx context.Context, userID uint, req *dto.APIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	key, prefix, err := apikey.Generate()
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	stored := &model.APIKey{
+		Credential: newCredential(userID, req.Name, req.Scopes, timestamp(s.now)),
+		Prefix:     prefix,
+		KeyHash:    apikey.Hash(key),
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if err := s.apiKeys.CreateAPIKey(ctx, stored); err != nil {
+		return nil, err
+	}
+	return &dto.APIKeyCreatedResponse{APIKeyResponse: *apiKeyResponse(stored), Key: key}, nil
+}
+
+func (s *apiKeyServiceImpl) FindAPIKeys(ctx context.Context, userID uint) (*dto.APIKeyListResponse, error) {
+	keys, err := s.apiKeys.FindAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return unpaginatedList(keys, apiKeyResponse), nil
+}
+
+func (s *apiKeyServiceImpl) RevokeAPIKey(ctx context.Context, userID, id uint) error {
+	return s.apiKeys.RevokeAPIKey(ctx, userID, id, timestamp(s.now))
+}
+
+func (s *apiKeyServiceImpl) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	prefix, ok := apikey.Prefix(key)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	stored, err := s.apiKeys.FindAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := timestamp(s.now)
+	if !apikey.Matches(key, stored.KeyHash) || stored.RevokedAt != nil || (stored.ExpiresAt != nil && !now.Before(*stored.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedPrecision {
+		// The request goes on even if the timestamp cannot be saved.
+		if err := s.apiKeys.TouchAPIKey(ctx, stored.ID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record API key use", "api_key_id", stored.ID, "error", err)
+		}
+	}
+	return &auth.Principal{UserID: stored.UserID, APIKeyID: stored.ID, Scopes: splitScopes(stored.Scopes)}, nil
+}
+
+func apiKeyResponse(key *model.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     splitScopes(key.Scopes),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
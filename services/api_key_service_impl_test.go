@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/apikey"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type apiKeyFixture struct {
+	*credentialOwner
+	service *apiKeyServiceImpl
+	keys    repositories.APIKeyRepository
+}
+
+func newAPIKeyFixture(t *testing.T) *apiKeyFixture {
+	users, owner := newCredentialOwner(t)
+	f := &apiKeyFixture{credentialOwner: owner, keys: repositories.NewMemoryAPIKeyRepository(users)}
+	f.service = NewAPIKeyService(f.keys).(*apiKeyServiceImpl)
+	f.service.now = owner.clock
+	return f
+}
+
+func (f *apiKeyFixture) create(t *testing.T, req *dto.APIKeyRequest) *dto.APIKeyCreatedResponse {
+	created, err := f.service.CreateAPIKey(context.Background(), f.user.ID, req)
+	require.NoError(t, err)
+	return created
+}
+
+func TestAPIKeyService_CreateAndVerify(t *testing.T) {
+	f := newAPIKeyFixture(t)
+
+	created := f.create(t, &dto.APIKeyRequest{Name: "batch", Scopes: []string{"users:update", "users:read_all", "users:update"}})
+
+	assert.True(t, strings.HasPrefix(created.Key, "lj_"+created.Prefix+"_"), created.Key)
+	assert.Equal(t, []string{"users:read_all", "users:update"}, created.Scopes)
+	assert.Equal(t, credentialTestTime, created.CreatedAt)
+	stored, err := f.keys.FindAPIKeyByPrefix(context.Background(), created.Prefix)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.KeyHash, created.Key)
+
+	principal, err := f.service.VerifyAPIKey(context.Background(), created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{UserID: f.user.ID, APIKeyID: created.ID, Scopes: []string{"users:read_all", "users:update"}}, principal)
+}
+
+func TestAPIKeyService_Verify_Rejects(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	expiresAt := credentialTestTime.Add(time.Hour)
+	expiring := f.create(t, &dto.APIKeyRequest{Name: "expiring", ExpiresAt: &expiresAt})
+	revoked := f.create(t, &dto.APIKeyRequest{Name: "revoked"})
+	require.NoError(t, f.service.RevokeAPIKey(context.Background(), f.user.ID, revoked.ID))
+	f.now = expiresAt
+
+	for name, key := range map[string]string{
+		"malformed":    "not-a-key",
+		"unknown":      "lj_0123456789abcdef_secret",
+		"wrong secret": "lj_" + expiring.Prefix + "_secret",
+		"expired":      expiring.Key,
+		"revoked":      revoked.Key,
+	} {
+		principal, err := f.service.VerifyAPIKey(context.Background(), key)
+
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, name)
+		assert.Nil(t, principal, name)
+	}
+}
+
+func TestAPIKeyService_Verify_RecordsLastUse(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	created := f.create(t, &dto.APIKeyRequest{Name: "batch"})
+	lastUsed := func() *time.Time {
+		list, err := f.service.FindAPIKeys(context.Background(), f.user.ID)
+		require.NoError(t, err)
+		return list.Items[0].LastUsedAt
+	}
+	verify := func() {
+		_, err := f.service.VerifyAPIKey(context.Background(), created.Key)
+		require.NoError(t, err)
+	}
+
+	assert.Nil(t, lastUsed())
+	verify()
+	assert.Equal(t, credentialTestTime, *lastUsed())
+
+	f.now = credentialTestTime.Add(30 * time.Second)
+	verify()
+	assert.Equal(t, credentialTestTime, *lastUsed())
+
+	f.now = credentialTestTime.Add(time.Minute)
+	verify()
+	assert.Equal(t, f.now, *lastUsed())
+}
+
+func TestAPIKeyService_Verify_TouchFailureIsIgnored(t *testing.T) {
+	repo := mocks.NewMockAPIKeyRepository(gomock.NewController(t))
+	key, prefix, err := apikey.Generate()
+	require.NoError(t, err)
+	repo.EXPECT().FindAPIKeyByPrefix(gomock.Any(), prefix).Return(&model.APIKey{Credential: model.Credential{ID: 4, UserID: 1}, KeyHash: apikey.Hash(key)}, nil)
+	repo.EXPECT().TouchAPIKey(gomock.Any(), uint(4), gomock.Any()).Return(errDatabase)
+
+	principal, err := NewAPIKeyService(repo).VerifyAPIKey(context.Background(), key)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), principal.APIKeyID)
+}
+
+func TestAPIKeyService_FindAndRevoke(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	created := f.create(t, &dto.APIKeyRequest{Name: "batch"})
+
+	require.NoError(t, f.service.RevokeAPIKey(context.Background(), f.user.ID, created.ID))
+
+	list, err := f.service.FindAPIKeys(context.Background(), f.user.ID)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, int64(1), list.Total)
+	assert.Equal(t, []string{}, list.Items[0].Scopes)
+	assert.Equal(t, credentialTestTime, *list.Items[0].RevokedAt)
+	assert.ErrorIs(t, f.service.RevokeAPIKey(context.Background(), f.user.ID+1, created.ID), repositories.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_Create_UnknownUser(t *testing.T) {
+	f := newAPIKeyFixture(t)
+
+	_, err := f.service.CreateAPIKey(context.Background(), f.user.ID+1, &dto.APIKeyRequest{Name: "batch"})
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+}
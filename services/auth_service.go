@@ -0,0 +1,26 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"context"
+)
+
+var (
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "refresh token is invalid or expired")
+	ErrInvalidAccessToken  = apperror.Unauthorized("invalid_token", "access token is invalid or expired")
+)
+
+type AuthService interface {
+	// Login starts a new session: a new refresh token family.
+	Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error)
+	// Refresh exchanges a refresh token for new tokens; the presented token
+	// can not be used again. Presenting a token that was already used
+	// revokes its whole family, since either copy may be a stolen one.
+	Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.TokenResponse, error)
+	// Logout revokes the family of the refresh token. Unknown tokens are
+	// ignored.
+	Logout(ctx context.Context, req *dto.RefreshRequest) error
+	VerifyAccessToken(ctx context.Context, token string) (*auth.Principal, error)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/token"
+	"Learn_Jenkins/repositories"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+const tokenTypeBearer = "Bearer"
+
+type authServiceImpl struct {
+	userService   UserService
+	refreshTokens repositories.RefreshTokenRepository
+	accessTokens  *token.Manager
+	refreshTTL    time.Duration
+	now           func() time.Time
+}
+
+func NewAuthService(userService UserService, refreshTokens repositories.RefreshTokenRepository, accessTokens *token.Manager, refreshTTL time.Duration) AuthService {
+	return &authServiceImpl{
+		userService:   userService,
+		refreshTokens: refreshTokens,
+		accessTokens:  accessTokens,
+		refreshTTL:    refreshTTL,
+		now:           time.Now,
+	}
+}
+
+func (s *authServiceImpl) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	user, err := s.userService.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := randomToken()
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	resp, refresh, err := s.issue(user, family)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokens.CreateRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *authServiceImpl) Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.TokenResponse, error) {
+	stored, err := s.refreshTokens.FindRefreshTokenByHash(ctx, hashRefreshToken(req.RefreshToken))
+	if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.RevokedAt != nil {
+		return nil, s.revokeReused(ctx, stored)
+	}
+	if !s.now().Before(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userService.FindUserByID(ctx, stored.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, next, err := s.issue(user, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.refreshTokens.RotateRefreshToken(ctx, stored.ID, next)
+	if errors.Is(err, repositories.ErrRefreshTokenRevoked) {
+		// A concurrent request rotated the same token first.
+		return nil, s.revokeReused(ctx, stored)
+	}
+	if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *authServiceImpl) Logout(ctx context.Context, req *dto.RefreshRequest) error {
+	stored, err := s.refreshTokens.FindRefreshTokenByHash(ctx, hashRefreshToken(req.RefreshToken))
+	if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeRefreshTokenFamily(ctx, stored.FamilyID, s.now().UTC())
+}
+
+func (s *authServiceImpl) VerifyAccessToken(ctx context.Context, accessToken string) (*auth.Principal, error) {
+	claims, err := s.accessTokens.Parse(accessToken)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	id, err := claims.UserID()
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	return &auth.Principal{UserID: id, Username: claims.Username}, nil
+}
+
+// issue signs an access token for user and creates the refresh token that
+// goes with it; the caller stores the refresh token.
+func (s *authServiceImpl) issue(user *dto.UserResponse, family string) (*dto.TokenResponse, *model.RefreshToken, error) {
+	accessToken, err := s.accessTokens.Issue(user.ID, user.Username)
+	if err != nil {
+		return nil, nil, apperror.Internal(err)
+	}
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, nil, apperror.Internal(err)
+	}
+
+	now := s.now().UTC()
+	stored := &model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  family,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(s.refreshTTL),
+		CreatedAt: now,
+	}
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    int64(s.accessTokens.TTL().Seconds()),
+		RefreshToken: refreshToken,
+	}, stored, nil
+}
+
+func (s *authServiceImpl) revokeReused(ctx context.Context, stored *model.RefreshToken) error {
+	slog.WarnContext(ctx, "revoked refresh token reused, revoking its family", "user_id", stored.UserID, "family_id", stored.FamilyID)
+	if err := s.refreshTokens.RevokeRefreshTokenFamily(ctx, stored.FamilyID, s.now().UTC()); err != nil {
+		return err
+	}
+	return ErrInvalidRefreshToken
+}
+
+// randomToken returns 256 random bits, URL-safe encoded.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken needs no salt or slow hash: the token is random, not a
+// password, so a database leak cannot be reversed into usable tokens.
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/token"
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testAccessTokens = token.NewManager([]byte("0123456789abcdef0123456789abcdef"), "test", 15*time.Minute)
+
+type authFixture struct {
+	service *authServiceImpl
+	users   UserService
+	tokens  repositories.RefreshTokenRepository
+	user    *dto.UserResponse
+}
+
+// newAuthFixture wires the service to in-memory repositories holding one
+// user, arthur, whose password is testPassword.
+func newAuthFixture(t *testing.T) *authFixture {
+	tokens := repositories.NewMemoryRefreshTokenRepository()
+	users := NewUserService(repositories.NewMemoryUserRepository(), tokens, testHasher)
+	user, err := users.CreateUser(context.Background(), &dto.UserRequest{Username: "arthur", Password: testPassword})
+	require.NoError(t, err)
+
+	service := NewAuthService(users, tokens, testAccessTokens, time.Hour).(*authServiceImpl)
+	return &authFixture{service: service, users: users, tokens: tokens, user: user}
+}
+
+func (f *authFixture) login(t *testing.T) *dto.TokenResponse {
+	resp, err := f.service.Login(context.Background(), &dto.LoginRequest{Username: "arthur", Password: testPassword})
+	require.NoError(t, err)
+	return resp
+}
+
+func (f *authFixture) refresh(refreshToken string) (*dto.TokenResponse, error) {
+	return f.service.Refresh(context.Background(), &dto.RefreshRequest{RefreshToken: refreshToken})
+}
+
+func TestAuthService_Login(t *testing.T) {
+	f := newAuthFixture(t)
+
+	resp := f.login(t)
+
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int64(900), resp.ExpiresIn)
+	principal, err := f.service.VerifyAccessToken(context.Background(), resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, f.user.ID, principal.UserID)
+	assert.Equal(t, "arthur", principal.Username)
+
+	stored, err := f.tokens.FindRefreshTokenByHash(context.Background(), hashRefreshToken(resp.RefreshToken))
+	require.NoError(t, err)
+	assert.Equal(t, f.user.ID, stored.UserID)
+	assert.NotEqual(t, resp.RefreshToken, stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+
+	other := f.login(t)
+	otherStored, err := f.tokens.FindRefreshTokenByHash(context.Background(), hashRefreshToken(other.RefreshToken))
+	require.NoError(t, err)
+	assert.NotEqual(t, stored.FamilyID, otherStored.FamilyID, "every login starts a new family")
+}
+
+func TestAuthService_Login_InvalidCredentials(t *testing.T) {
+	f := newAuthFixture(t)
+
+	for _, req := range []*dto.LoginRequest{
+		{Username: "arthur", Password: "wrong-Password-1"},
+		{Username: "nobody", Password: testPassword},
+	} {
+		resp, err := f.service.Login(context.Background(), req)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Nil(t, resp)
+	}
+}
+
+func TestAuthService_Refresh_Rotates(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+
+	second, err := f.refresh(first.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	_, err = f.service.VerifyAccessToken(context.Background(), second.AccessToken)
+	assert.NoError(t, err)
+
+	third, err := f.refresh(second.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, second.RefreshToken, third.RefreshToken)
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+	other := f.login(t)
+	second, err := f.refresh(first.RefreshToken)
+	require.NoError(t, err)
+
+	_, err = f.refresh(first.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = f.refresh(second.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken, "the token issued by the rotation is revoked too")
+	_, err = f.refresh(other.RefreshToken)
+	assert.NoError(t, err, "other sessions are not affected")
+}
+
+func TestAuthService_Refresh_Rejected(t *testing.T) {
+	t.Run("unknown", func(t *testing.T) {
+		f := newAuthFixture(t)
+
+		_, err := f.refresh("not-a-token")
+
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		f := newAuthFixture(t)
+		resp := f.login(t)
+		f.service.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+		_, err := f.refresh(resp.RefreshToken)
+
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		f := newAuthFixture(t)
+		resp := f.login(t)
+		require.NoError(t, f.users.DeleteUser(context.Background(), f.user.ID))
+
+		_, err := f.refresh(resp.RefreshToken)
+
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+}
+
+func TestAuthService_Refresh_ConcurrentRotation(t *testing.T) {
+	f := newAuthFixture(t)
+	tokens := mocks.NewMockRefreshTokenRepository(gomock.NewController(t))
+	f.service.refreshTokens = tokens
+	stored := &model.RefreshToken{ID: 3, UserID: f.user.ID, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+
+	tokens.EXPECT().FindRefreshTokenByHash(gomock.Any(), hashRefreshToken("refresh")).Return(stored, nil)
+	tokens.EXPECT().RotateRefreshToken(gomock.Any(), uint(3), gomock.Any()).Return(repositories.ErrRefreshTokenRevoked)
+	tokens.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), "family", gomock.Any()).Return(nil)
+
+	resp, err := f.refresh("refresh")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Nil(t, resp)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+	other := f.login(t)
+	second, err := f.refresh(first.RefreshToken)
+	require.NoError(t, err)
+
+	require.NoError(t, f.service.Logout(context.Background(), &dto.RefreshRequest{RefreshToken: second.RefreshToken}))
+
+	_, err = f.refresh(second.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	_, err = f.refresh(other.RefreshToken)
+	assert.NoError(t, err)
+	assert.NoError(t, f.service.Logout(context.Background(), &dto.RefreshRequest{RefreshToken: "unknown"}))
+	assert.NoError(t, f.service.Logout(context.Background(), &dto.RefreshRequest{RefreshToken: second.RefreshToken}))
+}
+
+func TestAuthService_PasswordChangeRevokesRefreshTokens(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+	second := f.login(t)
+	changed := "another-Horse-battery"
+
+	_, err := f.users.PatchUser(context.Background(), f.user.ID, &dto.UserPatchRequest{Password: &changed})
+	require.NoError(t, err)
+
+	for _, session := range []*dto.TokenResponse{first, second} {
+		_, err = f.refresh(session.RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	}
+	_, err = f.service.Login(context.Background(), &dto.LoginRequest{Username: "arthur", Password: changed})
+	assert.NoError(t, err)
+}
+
+func TestAuthService_VerifyAccessToken_Invalid(t *testing.T) {
+	f := newAuthFixture(t)
+	forged, err := token.NewManager([]byte("ffffffffffffffffffffffffffffffff"), "test", time.Minute).Issue(f.user.ID, "arthur")
+	require.NoError(t, err)
+
+	for _, accessToken := range []string{"", "not-a-token", forged} {
+		principal, err := f.service.VerifyAccessToken(context.Background(), accessToken)
+		assert.ErrorIs(t, err, ErrInvalidAccessToken)
+		assert.Nil(t, principal)
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/model"
+	"strings"
+	"time"
+)
+
+// newCredential fills the fields API keys and signing clients share.
+func newCredential(userID uint, name string, scopes []string, createdAt time.Time) model.Credential {
+	return model.Credential{UserID: userID, Name: name, Scopes: joinScopes(scopes), CreatedAt: createdAt}
+}
+
+// timestamp is the current time on clock as credentials store it, to the
+// second.
+func timestamp(clock func() time.Time) time.Time {
+	return clock().UTC().Truncate(time.Second)
+}
+
+// joinScopes stores the requested scopes of a machine credential sorted,
+// without duplicates and separated by spaces.
+func joinScopes(scopes []string) string {
+	permissions := make([]authz.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		permissions = append(permissions, authz.Permission(scope))
+	}
+	return strings.Join(authz.PermissionNames(permissions), " ")
+}
+
+// splitScopes reverses joinScopes; it never returns nil, so that responses
+// show an empty list rather than null.
+func splitScopes(stored string) []string {
+	return append([]string{}, strings.Fields(stored)...)
+}
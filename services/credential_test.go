@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var credentialTestTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// credentialOwner is the user, arthur, whose API keys and signing clients a
+// test creates, and a clock the test can move.
+type credentialOwner struct {
+	user *model.User
+	now  time.Time
+}
+
+// newCredentialOwner returns the in-memory users repository holding the
+// owner, for the credential repository under test to share.
+func newCredentialOwner(t *testing.T) (repositories.UserRepository, *credentialOwner) {
+	users := repositories.NewMemoryUserRepository()
+	user, err := users.CreateUser(context.Background(), &dto.UserRequest{Username: "arthur"})
+	require.NoError(t, err)
+	return users, &credentialOwner{user: user, now: credentialTestTime}
+}
+
+func (o *credentialOwner) clock() time.Time {
+	return o.now
+}
+
+func TestTimestamp(t *testing.T) {
+	local := time.FixedZone("WIB", 7*60*60)
+	clock := func() time.Time { return time.Date(2025, 1, 1, 19, 0, 0, 999, local) }
+
+	assert.Equal(t, credentialTestTime, timestamp(clock))
+}
@@ -0,0 +1,17 @@
+package services
+
+import "Learn_Jenkins/domain/dto"
+
+// unpaginatedList wraps a collection that is always returned whole, such as
+// the roles or the credentials of a user, in the list envelope.
+func unpaginatedList[M, R any](items []M, respond func(M) R) *dto.ListResponse[R] {
+	responses := make([]R, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, respond(item))
+	}
+	return &dto.ListResponse[R]{
+		Items: responses,
+		Total: int64(len(responses)),
+		Limit: len(responses),
+	}
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"context"
+)
+
+// ErrUnknownCaller is returned for credentials that are still valid but name
+// a user that has since been deleted.
+var ErrUnknownCaller = apperror.Unauthorized("unknown_caller", "the authenticated user no longer exists")
+
+type RoleService interface {
+	FindAllRoles(ctx context.Context) (*dto.RoleListResponse, error)
+	FindUserRoles(ctx context.Context, userID uint) (*dto.RoleListResponse, error)
+	AssignRole(ctx context.Context, userID uint, role string) error
+	RevokeRole(ctx context.Context, userID uint, role string) error
+	// Subject returns the caller with the union of the permissions of their
+	// roles, as checked by authz.Policy. For API keys and signing clients,
+	// only the permissions among the credential's scopes are kept.
+	Subject(ctx context.Context, principal *auth.Principal) (*authz.Subject, error)
+	// UserPermissions returns the union of the permissions of the user's
+	// roles. Unknown users hold none, leaving the 404 to the handler.
+	UserPermissions(ctx context.Context, userID uint) ([]authz.Permission, error)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/repositories"
+	"context"
+	"errors"
+	"slices"
+)
+
+type roleServiceImpl struct {
+	roleRepository repositories.RoleRepository
+}
+
+func NewRoleService(roleRepository repositories.RoleRepository) RoleService {
+	return &roleServiceImpl{roleRepository: roleRepository}
+}
+
+func (s *roleServiceImpl) FindAllRoles(ctx context.Context) (*dto.RoleListResponse, error) {
+	roles, err := s.roleRepository.FindAllRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unpaginatedList(roles, roleResponse), nil
+}
+
+func (s *roleServiceImpl) FindUserRoles(ctx context.Context, userID uint) (*dto.RoleListResponse, error) {
+	roles, err := s.roleRepository.FindRolesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return unpaginatedList(roles, roleResponse), nil
+}
+
+func (s *roleServiceImpl) AssignRole(ctx context.Context, userID uint, role string) error {
+	return s.roleRepository.AssignRole(ctx, userID, role)
+}
+
+func (s *roleServiceImpl) RevokeRole(ctx context.Context, userID uint, role string) error {
+	return s.roleRepository.RevokeRole(ctx, userID, role)
+}
+
+func (s *roleServiceImpl) Subject(ctx context.Context, principal *auth.Principal) (*authz.Subject, error) {
+	roles, err := s.roleRepository.FindRolesByUserID(ctx, principal.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrUnknownCaller
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subject := &authz.Subject{UserID: principal.UserID, Delegated: principal.Delegated()}
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			if principal.Delegated() && !slices.Contains(principal.Scopes, permission.Name) {
+				continue
+			}
+			subject.Permissions = append(subject.Permissions, authz.Permission(permission.Name))
+		}
+	}
+	return subject, nil
+}
+
+func (s *roleServiceImpl) UserPermissions(ctx context.Context, userID uint) ([]authz.Permission, error) {
+	roles, err := s.roleRepository.FindRolesByUserID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []authz.Permission
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, authz.Permission(permission.Name))
+		}
+	}
+	return permissions, nil
+}
+
+func roleResponse(role *model.Role) *dto.RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+	return &dto.RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
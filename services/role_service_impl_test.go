@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"Learn_Jenkins/domain/authz"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newRoleFixture(t *testing.T) (RoleService, *model.User) {
+	users := repositories.NewMemoryUserRepository()
+	user, err := users.CreateUser(context.Background(), &dto.UserRequest{Username: "arthur"})
+	require.NoError(t, err)
+	return NewRoleService(repositories.NewMemoryRoleRepository(users)), user
+}
+
+func TestRoleService_FindAllRoles(t *testing.T) {
+	service, _ := newRoleFixture(t)
+
+	list, err := service.FindAllRoles(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), list.Total)
+	assert.Equal(t, &dto.RoleResponse{
+		Name:        authz.RoleViewer,
+		Description: "Reads any user",
+		Permissions: []string{"users:read_all"},
+	}, list.Items[2])
+}
+
+func TestRoleService_AssignAndRevoke(t *testing.T) {
+	service, user := newRoleFixture(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.AssignRole(ctx, user.ID, authz.RoleOperator))
+	list, err := service.FindUserRoles(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, authz.RoleOperator, list.Items[0].Name)
+
+	require.NoError(t, service.RevokeRole(ctx, user.ID, authz.RoleOperator))
+	list, err = service.FindUserRoles(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+	assert.ErrorIs(t, service.AssignRole(ctx, user.ID, "superuser"), repositories.ErrRoleNotFound)
+}
+
+func TestRoleService_Subject(t *testing.T) {
+	service, user := newRoleFixture(t)
+	ctx := context.Background()
+	principal := &auth.Principal{UserID: user.ID, Username: user.Username}
+
+	subject, err := service.Subject(ctx, principal)
+	require.NoError(t, err)
+	assert.Equal(t, &authz.Subject{UserID: user.ID}, subject)
+
+	require.NoError(t, service.AssignRole(ctx, user.ID, authz.RoleOperator))
+	require.NoError(t, service.AssignRole(ctx, user.ID, authz.RoleViewer))
+	subject, err = service.Subject(ctx, principal)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []authz.Permission{authz.PermUsersReadAll, authz.PermUsersUpdate, authz.PermUsersReadAll}, subject.Permissions)
+	assert.NoError(t, authz.DefaultPolicy().Authorize(subject, authz.ActionUpdateUser, authz.Resource{UserID: user.ID + 1}))
+}
+
+func TestRoleService_Subject_APIKeyScopes(t *testing.T) {
+	service, user := newRoleFixture(t)
+	ctx := context.Background()
+	require.NoError(t, service.AssignRole(ctx, user.ID, authz.RoleOperator))
+
+	subject, err := service.Subject(ctx, &auth.Principal{UserID: user.ID, APIKeyID: 3, Scopes: []string{"users:read_all", "users:delete"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, &authz.Subject{UserID: user.ID, Permissions: []authz.Permission{authz.PermUsersReadAll}, Delegated: true}, subject)
+}
+
+func TestRoleService_Subject_SigningClientScopes(t *testing.T) {
+	service, user := newRoleFixture(t)
+	ctx := context.Background()
+	require.NoError(t, service.AssignRole(ctx, user.ID, authz.RoleAdmin))
+
+	subject, err := service.Subject(ctx, &auth.Principal{UserID: user.ID, SigningClientID: 3})
+	require.NoError(t, err)
+	assert.Equal(t, &authz.Subject{UserID: user.ID, Delegated: true}, subject, "a client without scopes gets none of the owner's permissions")
+
+	subject, err = service.Subject(ctx, &auth.Principal{UserID: user.ID, SigningClientID: 3, Scopes: []string{"users:read_all"}})
+	require.NoError(t, err)
+	assert.Equal(t, &authz.Subject{UserID: user.ID, Permissions: []authz.Permission{authz.PermUsersReadAll}, Delegated: true}, subject)
+}
+
+func TestRoleService_UserPermissions(t *testing.T) {
+	service, user := newRoleFixture(t)
+	ctx := context.Background()
+	require.NoError(t, service.AssignRole(ctx, user.ID, authz.RoleOperator))
+
+	permissions, err := service.UserPermissions(ctx, user.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []authz.Permission{authz.PermUsersReadAll, authz.PermUsersUpdate}, permissions)
+
+	permissions, err = service.UserPermissions(ctx, user.ID+1)
+	require.NoError(t, err, "the handler reports unknown users")
+	assert.Empty(t, permissions)
+}
+
+func TestRoleService_Subject_DeletedUser(t *testing.T) {
+	service, user := newRoleFixture(t)
+
+	_, err := service.Subject(context.Background(), &auth.Principal{UserID: user.ID + 1})
+
+	assert.ErrorIs(t, err, ErrUnknownCaller)
+}
+
+func TestRoleService_Subject_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockRoleRepository(gomock.NewController(t))
+	repo.EXPECT().FindRolesByUserID(gomock.Any(), uint(1)).Return(nil, errDatabase)
+
+	_, err := NewRoleService(repo).Subject(context.Background(), &auth.Principal{UserID: 1})
+
+	assert.ErrorIs(t, err, errDatabase)
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/signature"
+	"context"
+)
+
+var (
+	// ErrInvalidSignature does not say whether the client is unknown or
+	// revoked, or the signature wrong.
+	ErrInvalidSignature = apperror.Unauthorized("invalid_signature", "request signature is invalid")
+	ErrSignatureExpired = apperror.Unauthorized("signature_expired", "request timestamp is too far from the server clock")
+	ErrReplayedRequest  = apperror.Unauthorized("replayed_request", "request nonce has already been used")
+)
+
+type SigningClientService interface {
+	// CreateSigningClient returns the new client with its secret, which
+	// cannot be shown again.
+	CreateSigningClient(ctx context.Context, userID uint, req *dto.SigningClientRequest) (*dto.SigningClientCreatedResponse, error)
+	FindSigningClients(ctx context.Context, userID uint) (*dto.SigningClientListResponse, error)
+	RevokeSigningClient(ctx context.Context, userID, id uint) error
+	// VerifySignature accepts each nonce once, and only while the timestamp
+	// is within the allowed clock skew.
+	VerifySignature(ctx context.Context, credentials *signature.Credentials, stringToSign string) (*auth.Principal, error)
+	// PurgeExpiredNonces forgets the nonces of requests that would now be
+	// refused as expired anyway, and returns how many it removed.
+	PurgeExpiredNonces(ctx context.Context) (int64, error)
+}
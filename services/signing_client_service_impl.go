@@ -0,0 +1,134 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/sealer"
+	"Learn_Jenkins/pkg/signature"
+	"Learn_Jenkins/repositories"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// nonceBatchSize bounds the nonces removed per statement, so that a purge
+// never holds the table long enough to slow signed requests down.
+const nonceBatchSize = 500
+
+type signingClientServiceImpl struct {
+	clients repositories.SigningClientRepository
+	secrets *sealer.Sealer
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+// NewSigningClientService stores the client secrets sealed with secrets, and
+// accepts signed requests whose timestamp is less than maxSkew away from the
+// server clock, either way.
+func NewSigningClientService(clients repositories.SigningClientRepository, secrets *sealer.Sealer, maxSkew time.Duration) SigningClientService {
+	return &signingClientServiceImpl{clients: clients, secrets: secrets, maxSkew: maxSkew, now: time.Now}
+}
+
+func (s *signingClientServiceImpl) CreateSigningClient(ctx context.Context, userID uint, req *dto.SigningClientRequest) (*dto.SigningClientCreatedResponse, error) {
+	clientID, secret, err := signature.NewClient()
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+	sealed, err := s.secrets.Seal(secret, clientID)
+	if err != nil {
+		return nil, apperror.Internal(err)
+	}
+
+	stored := &model.SigningClient{
+		Credential: newCredential(userID, req.Name, req.Scopes, timestamp(s.now)),
+		ClientID:   clientID,
+		Secret:     sealed,
+	}
+	if err := s.clients.CreateSigningClient(ctx, stored); err != nil {
+		return nil, err
+	}
+	return &dto.SigningClientCreatedResponse{SigningClientResponse: *signingClientResponse(stored), Secret: secret}, nil
+}
+
+func (s *signingClientServiceImpl) FindSigningClients(ctx context.Context, userID uint) (*dto.SigningClientListResponse, error) {
+	clients, err := s.clients.FindSigningClientsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return unpaginatedList(clients, signingClientResponse), nil
+}
+
+func (s *signingClientServiceImpl) RevokeSigningClient(ctx context.Context, userID, id uint) error {
+	return s.clients.RevokeSigningClient(ctx, userID, id, timestamp(s.now))
+}
+
+func (s *signingClientServiceImpl) VerifySignature(ctx context.Context, credentials *signature.Credentials, stringToSign string) (*auth.Principal, error) {
+	client, err := s.clients.FindSigningClientByClientID(ctx, credentials.ClientID)
+	if errors.Is(err, repositories.ErrSigningClientNotFound) {
+		return nil, ErrInvalidSignature
+	}
+	if err != nil {
+		return nil, err
+	}
+	if client.RevokedAt != nil {
+		return nil, ErrInvalidSignature
+	}
+	secret, err := s.secrets.Open(client.Secret, client.ClientID)
+	if err != nil {
+		// The encryption key changed since the client was created; it has to
+		// be replaced.
+		slog.WarnContext(ctx, "stored signing secret cannot be decrypted", "signing_client_id", client.ID, "error", err)
+		return nil, ErrInvalidSignature
+	}
+	// The signature is checked first, so that only the client itself can
+	// learn about the clock or use up its nonces.
+	if !signature.Verify([]byte(secret), stringToSign, credentials.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	now := timestamp(s.now)
+	if skew := now.Sub(credentials.Timestamp); skew >= s.maxSkew || skew <= -s.maxSkew {
+		return nil, ErrSignatureExpired
+	}
+	// From timestamp + maxSkew on the request is refused as expired, so the
+	// nonce no longer needs to be remembered.
+	nonce := &model.SignatureNonce{
+		ClientID:  client.ClientID,
+		Nonce:     credentials.Nonce,
+		ExpiresAt: credentials.Timestamp.Add(s.maxSkew).UTC(),
+	}
+	err = s.clients.UseNonce(ctx, nonce)
+	if errors.Is(err, repositories.ErrNonceReused) {
+		return nil, ErrReplayedRequest
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &auth.Principal{UserID: client.UserID, SigningClientID: client.ID, Scopes: splitScopes(client.Scopes)}, nil
+}
+
+func (s *signingClientServiceImpl) PurgeExpiredNonces(ctx context.Context) (int64, error) {
+	now := timestamp(s.now)
+	var total int64
+	for {
+		purged, err := s.clients.PurgeExpiredNonces(ctx, now, nonceBatchSize)
+		total += purged
+		if err != nil || purged < nonceBatchSize {
+			return total, err
+		}
+	}
+}
+
+func signingClientResponse(client *model.SigningClient) *dto.SigningClientResponse {
+	return &dto.SigningClientResponse{
+		ID:        client.ID,
+		Name:      client.Name,
+		ClientID:  client.ClientID,
+		Scopes:    splitScopes(client.Scopes),
+		RevokedAt: client.RevokedAt,
+		CreatedAt: client.CreatedAt,
+	}
+}
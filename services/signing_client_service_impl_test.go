@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/auth"
+	"Learn_Jenkins/pkg/sealer"
+	"Learn_Jenkins/pkg/signature"
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testMaxSkew = 5 * time.Minute
+
+var testSealer = newSealer()
+
+func newSealer() *sealer.Sealer {
+	s, err := sealer.NewRandom()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+type signingClientFixture struct {
+	*credentialOwner
+	service *signingClientServiceImpl
+	clients repositories.SigningClientRepository
+}
+
+func newSigningClientFixture(t *testing.T) *signingClientFixture {
+	users, owner := newCredentialOwner(t)
+	f := &signingClientFixture{credentialOwner: owner, clients: repositories.NewMemorySigningClientRepository(users)}
+	f.service = NewSigningClientService(f.clients, testSealer, testMaxSkew).(*signingClientServiceImpl)
+	f.service.now = owner.clock
+	return f
+}
+
+func (f *signingClientFixture) create(t *testing.T) *dto.SigningClientCreatedResponse {
+	created, err := f.service.CreateSigningClient(context.Background(), f.user.ID, &dto.SigningClientRequest{
+		Name:   "billing",
+		Scopes: []string{"users:read_all", "users:delete", "users:read_all"},
+	})
+	require.NoError(t, err)
+	return created
+}
+
+// sign returns the credentials and string to sign of a request signed with
+// secret at timestamp.
+func sign(clientID, secret string, timestamp time.Time, nonce string) (*signature.Credentials, string) {
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	stringToSign := signature.StringToSign(req, timestamp, nonce, nil)
+	return &signature.Credentials{
+		ClientID:  clientID,
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Signature: signature.Sign([]byte(secret), stringToSign),
+	}, stringToSign
+}
+
+func TestSigningClientService_CreateAndVerify(t *testing.T) {
+	f := newSigningClientFixture(t)
+	created := f.create(t)
+	assert.Equal(t, "billing", created.Name)
+	assert.Regexp(t, `^sc_[0-9a-f]{16}$`, created.ClientID)
+	assert.NotEmpty(t, created.Secret)
+	assert.Equal(t, []string{"users:delete", "users:read_all"}, created.Scopes)
+	assert.Equal(t, credentialTestTime, created.CreatedAt)
+
+	credentials, stringToSign := sign(created.ClientID, created.Secret, credentialTestTime.Add(-time.Minute), "nonce-0123456789")
+	principal, err := f.service.VerifySignature(context.Background(), credentials, stringToSign)
+
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{UserID: f.user.ID, SigningClientID: created.ID, Scopes: []string{"users:delete", "users:read_all"}}, principal)
+	assert.True(t, principal.Delegated())
+}
+
+func TestSigningClientService_StoresSecretEncrypted(t *testing.T) {
+	f := newSigningClientFixture(t)
+	created := f.create(t)
+
+	stored, err := f.clients.FindSigningClientByClientID(context.Background(), created.ClientID)
+	require.NoError(t, err)
+	assert.NotContains(t, stored.Secret, created.Secret)
+	opened, err := testSealer.Open(stored.Secret, created.ClientID)
+	assert.NoError(t, err)
+	assert.Equal(t, created.Secret, opened)
+
+	// A service holding another key cannot use the secret.
+	f.service.secrets = newSealer()
+	credentials, stringToSign := sign(created.ClientID, created.Secret, credentialTestTime, "nonce-0123456789")
+	principal, err := f.service.VerifySignature(context.Background(), credentials, stringToSign)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.Nil(t, principal)
+}
+
+func TestSigningClientService_Verify_Rejects(t *testing.T) {
+	f := newSigningClientFixture(t)
+	created := f.create(t)
+	revoked := f.create(t)
+	require.NoError(t, f.service.RevokeSigningClient(context.Background(), f.user.ID, revoked.ID))
+
+	tests := []struct {
+		name      string
+		clientID  string
+		secret    string
+		timestamp time.Time
+		err       error
+	}{
+		{"unknown client", "sc_0123456789abcdef", created.Secret, credentialTestTime, ErrInvalidSignature},
+		{"wrong secret", created.ClientID, revoked.Secret, credentialTestTime, ErrInvalidSignature},
+		{"revoked client", revoked.ClientID, revoked.Secret, credentialTestTime, ErrInvalidSignature},
+		{"too old", created.ClientID, created.Secret, credentialTestTime.Add(-testMaxSkew), ErrSignatureExpired},
+		{"too far ahead", created.ClientID, created.Secret, credentialTestTime.Add(testMaxSkew), ErrSignatureExpired},
+	}
+
+	for _, tt := range tests {
+		credentials, stringToSign := sign(tt.clientID, tt.secret, tt.timestamp, "nonce-0123456789")
+
+		principal, err := f.service.VerifySignature(context.Background(), credentials, stringToSign)
+
+		assert.ErrorIs(t, err, tt.err, tt.name)
+		assert.Nil(t, principal, tt.name)
+	}
+}
+
+func TestSigningClientService_Verify_RejectsReplays(t *testing.T) {
+	f := newSigningClientFixture(t)
+	created := f.create(t)
+	credentials, stringToSign := sign(created.ClientID, created.Secret, credentialTestTime, "nonce-0123456789")
+
+	_, err := f.service.VerifySignature(context.Background(), credentials, stringToSign)
+	require.NoError(t, err)
+
+	f.now = credentialTestTime.Add(testMaxSkew - time.Second)
+	_, err = f.service.VerifySignature(context.Background(), credentials, stringToSign)
+	assert.ErrorIs(t, err, ErrReplayedRequest)
+
+	f.now = credentialTestTime.Add(testMaxSkew)
+	_, err = f.service.VerifySignature(context.Background(), credentials, stringToSign)
+	assert.ErrorIs(t, err, ErrSignatureExpired)
+}
+
+func TestSigningClientService_Verify_RepositoryFailure(t *testing.T) {
+	repo := mocks.NewMockSigningClientRepository(gomock.NewController(t))
+	credentials, stringToSign := sign("sc_0123456789abcdef", "secret", time.Now(), "nonce-0123456789")
+	sealed, err := testSealer.Seal("secret", "sc_0123456789abcdef")
+	require.NoError(t, err)
+	repo.EXPECT().FindSigningClientByClientID(gomock.Any(), "sc_0123456789abcdef").
+		Return(&model.SigningClient{Credential: model.Credential{ID: 2, UserID: 1}, ClientID: "sc_0123456789abcdef", Secret: sealed}, nil)
+	repo.EXPECT().UseNonce(gomock.Any(), gomock.Any()).Return(errDatabase)
+
+	principal, err := NewSigningClientService(repo, testSealer, testMaxSkew).VerifySignature(context.Background(), credentials, stringToSign)
+
+	assert.ErrorIs(t, err, errDatabase)
+	assert.Nil(t, principal)
+}
+
+func TestSigningClientService_PurgeExpiredNonces(t *testing.T) {
+	f := newSigningClientFixture(t)
+	created := f.create(t)
+	credentials, stringToSign := sign(created.ClientID, created.Secret, credentialTestTime, "nonce-0123456789")
+	_, err := f.service.VerifySignature(context.Background(), credentials, stringToSign)
+	require.NoError(t, err)
+
+	f.now = credentialTestTime.Add(testMaxSkew - time.Second)
+	purged, err := f.service.PurgeExpiredNonces(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	f.now = credentialTestTime.Add(testMaxSkew)
+	purged, err = f.service.PurgeExpiredNonces(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestSigningClientService_PurgeExpiredNonces_Batches(t *testing.T) {
+	repo := mocks.NewMockSigningClientRepository(gomock.NewController(t))
+	gomock.InOrder(
+		repo.EXPECT().PurgeExpiredNonces(gomock.Any(), gomock.Any(), nonceBatchSize).Return(int64(nonceBatchSize), nil),
+		repo.EXPECT().PurgeExpiredNonces(gomock.Any(), gomock.Any(), nonceBatchSize).Return(int64(nonceBatchSize), nil),
+		repo.EXPECT().PurgeExpiredNonces(gomock.Any(), gomock.Any(), nonceBatchSize).Return(int64(3), nil),
+	)
+
+	purged, err := NewSigningClientService(repo, testSealer, testMaxSkew).PurgeExpiredNonces(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2*nonceBatchSize+3), purged)
+}
+
+func TestSigningClientService_PurgeExpiredNonces_RepositoryFailure(t *testing.T) {
+	repo := mocks.NewMockSigningClientRepository(gomock.NewController(t))
+	gomock.InOrder(
+		repo.EXPECT().PurgeExpiredNonces(gomock.Any(), gomock.Any(), nonceBatchSize).Return(int64(nonceBatchSize), nil),
+		repo.EXPECT().PurgeExpiredNonces(gomock.Any(), gomock.Any(), nonceBatchSize).Return(int64(0), errDatabase),
+	)
+
+	purged, err := NewSigningClientService(repo, testSealer, testMaxSkew).PurgeExpiredNonces(context.Background())
+
+	assert.ErrorIs(t, err, errDatabase)
+	assert.Equal(t, int64(nonceBatchSize), purged)
+}
+
+func TestSigningClientService_FindAndRevoke(t *testing.T) {
+	f := newSigningClientFixture(t)
+	created := f.create(t)
+
+	require.NoError(t, f.service.RevokeSigningClient(context.Background(), f.user.ID, created.ID))
+
+	list, err := f.service.FindSigningClients(context.Background(), f.user.ID)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, int64(1), list.Total)
+	assert.Equal(t, created.ClientID, list.Items[0].ClientID)
+	assert.Equal(t, credentialTestTime, *list.Items[0].RevokedAt)
+	assert.ErrorIs(t, f.service.RevokeSigningClient(context.Background(), f.user.ID+1, created.ID), repositories.ErrSigningClientNotFound)
+}
+
+func TestSigningClientService_Create_UnknownUser(t *testing.T) {
+	f := newSigningClientFixture(t)
+
+	_, err := f.service.CreateSigningClient(context.Background(), f.user.ID+1, &dto.SigningClientRequest{Name: "billing"})
+
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+}
@@ -1,12 +1,28 @@
 package services
 
 import (
+	"Learn_Jenkins/domain/apperror"
 	"Learn_Jenkins/domain/dto"
 	"context"
 )
 
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ErrInvalidCredentials does not say whether the username or the password
+// was wrong.
+var ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid username or password")
+
 type UserService interface {
 	CreateUser(ctx context.Context, req *dto.UserRequest) (*dto.UserResponse, error)
 	FindUserByID(ctx context.Context, id uint) (*dto.UserResponse, error)
-	FindAllUsers(ctx context.Context) ([]*dto.UserResponse, error)
+	FindAllUsers(ctx context.Context, filter *dto.UserFilter) (*dto.UserListResponse, error)
+	UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*dto.UserResponse, error)
+	PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*dto.UserResponse, error)
+	DeleteUser(ctx context.Context, id uint) error
+	// Authenticate checks a username and password and upgrades the stored
+	// hash when it was made with outdated parameters.
+	Authenticate(ctx context.Context, username, password string) (*dto.UserResponse, error)
 }
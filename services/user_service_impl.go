@@ -1,21 +1,39 @@
 package services
 
 import (
+	"Learn_Jenkins/domain/apperror"
 	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/password"
 	"Learn_Jenkins/repositories"
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 )
 
 type userServiceImpl struct {
 	userRepository repositories.UserRepository
+	refreshTokens  repositories.RefreshTokenRepository
+	hasher         *password.Hasher
+	now            func() time.Time
 }
 
-func NewUserService(userRepository repositories.UserRepository) UserService {
-	return &userServiceImpl{userRepository: userRepository}
+func NewUserService(userRepository repositories.UserRepository, refreshTokens repositories.RefreshTokenRepository, hasher *password.Hasher) UserService {
+	return &userServiceImpl{
+		userRepository: userRepository,
+		refreshTokens:  refreshTokens,
+		hasher:         hasher,
+		now:            time.Now,
+	}
 }
 
 func (s *userServiceImpl) CreateUser(ctx context.Context, req *dto.UserRequest) (*dto.UserResponse, error) {
-	user, err := s.userRepository.CreateUser(ctx, req)
+	hashed, err := s.hashRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepository.CreateUser(ctx, hashed)
 	if err != nil {
 		return nil, err
 	}
@@ -36,17 +54,148 @@ func (s *userServiceImpl) FindUserByID(ctx context.Context, id uint) (*dto.UserR
 	}, nil
 }
 
-func (s *userServiceImpl) FindAllUsers(ctx context.Context) ([]*dto.UserResponse, error) {
-	users, err := s.userRepository.FindAllUsers(ctx)
+func (s *userServiceImpl) FindAllUsers(ctx context.Context, filter *dto.UserFilter) (*dto.UserListResponse, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	query := *filter
+	query.Limit++
+	users, total, err := s.userRepository.FindAllUsers(ctx, &query)
 	if err != nil {
 		return nil, err
 	}
-	var responses []*dto.UserResponse
+
+	hasMore := len(users) > filter.Limit
+	if hasMore {
+		users = users[:filter.Limit]
+	}
+	responses := make([]*dto.UserResponse, 0, len(users))
 	for _, user := range users {
 		responses = append(responses, &dto.UserResponse{
 			ID:       user.ID,
 			Username: user.Username,
 		})
 	}
-	return responses, nil
+	return &dto.UserListResponse{
+		Items:   responses,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+		HasMore: hasMore,
+	}, nil
+}
+
+func (s *userServiceImpl) UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*dto.UserResponse, error) {
+	hashed, err := s.hashRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepository.UpdateUser(ctx, id, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, id); err != nil {
+		return nil, err
+	}
+	return &dto.UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+	}, nil
+}
+
+func (s *userServiceImpl) PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*dto.UserResponse, error) {
+	if req.Password != nil {
+		hash, err := s.hash(*req.Password)
+		if err != nil {
+			return nil, err
+		}
+		hashed := *req
+		hashed.Password, hashed.PasswordHash = nil, &hash
+		req = &hashed
+	}
+	user, err := s.userRepository.PatchUser(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.PasswordHash != nil {
+		if err := s.revokeSessions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return &dto.UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+	}, nil
+}
+
+// revokeSessions signs the user out everywhere after a password change, so
+// that a refresh token obtained with the old password stops working.
+func (s *userServiceImpl) revokeSessions(ctx context.Context, userID uint) error {
+	return s.refreshTokens.RevokeUserRefreshTokens(ctx, userID, s.now().UTC())
+}
+
+func (s *userServiceImpl) DeleteUser(ctx context.Context, id uint) error {
+	return s.userRepository.DeleteUser(ctx, id)
+}
+
+func (s *userServiceImpl) Authenticate(ctx context.Context, username, plain string) (*dto.UserResponse, error) {
+	user, err := s.userRepository.FindUserByUsername(ctx, username)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		s.hasher.VerifyDummy(plain)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match, needsRehash, err := s.hasher.Verify(plain, string(user.PasswordHash))
+	if err != nil {
+		// Accounts created before passwords existed have an empty hash and
+		// cannot log in until a password is set.
+		slog.WarnContext(ctx, "stored password hash is unusable", "user_id", user.ID, "error", err)
+		return nil, ErrInvalidCredentials
+	}
+	if !match {
+		return nil, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		// The login succeeds even if the upgrade fails; it is retried on the
+		// next login.
+		if hash, err := s.hash(plain); err != nil {
+			slog.ErrorContext(ctx, "failed to rehash password", "user_id", user.ID, "error", err)
+		} else if _, err := s.userRepository.PatchUser(ctx, user.ID, &dto.UserPatchRequest{PasswordHash: &hash}); err != nil {
+			slog.ErrorContext(ctx, "failed to store rehashed password", "user_id", user.ID, "error", err)
+		}
+	}
+	return &dto.UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+	}, nil
+}
+
+// hashRequest returns a copy of req carrying the hash instead of the password.
+func (s *userServiceImpl) hashRequest(req *dto.UserRequest) (*dto.UserRequest, error) {
+	hash, err := s.hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
+	hashed := *req
+	hashed.Password, hashed.PasswordHash = "", hash
+	return &hashed, nil
+}
+
+func (s *userServiceImpl) hash(plain string) (model.PasswordHash, error) {
+	hash, err := s.hasher.Hash(plain)
+	if err != nil {
+		return "", apperror.Internal(err)
+	}
+	return model.PasswordHash(hash), nil
 }
@@ -1,216 +1,505 @@
-// package services
-
-// import (
-// 	"Learn_Jenkins/config"
-// 	"Learn_Jenkins/domain/dto"
-// 	"Learn_Jenkins/domain/model"
-// 	"Learn_Jenkins/repositories"
-// 	"context"
-// 	"fmt"
-// 	"os"
-// 	"testing"
-
-// 	"github.com/stretchr/testify/assert"
-// 	"gorm.io/gorm"
-// )
-
-// func setupTestDB(t *testing.T) *gorm.DB {
-// 	db, err := config.InitTestDatabase()
-// 	if err != nil {
-// 		t.Fatalf("failed to connect to test database: %v", err)
-// 	}
-
-// 	if err := db.AutoMigrate(&model.User{}); err != nil {
-// 		t.Fatalf("failed to migrate: %v", err)
-// 	}
-
-// 	if err := db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE").Error; err != nil {
-// 		t.Fatalf("failed to truncate users: %v", err)
-// 	}
-
-// 	t.Cleanup(func() {
-// 		sqlDB, _ := db.DB()
-// 		_ = sqlDB.Close()
-// 	})
-
-// 	return db
-// }
-
-// func TestMain(m *testing.M) {
-// 	err := config.CreateTestDatabase()
-// 	if err != nil {
-// 		fmt.Println(err)
-// 		panic(err)
-// 	}
-
-// 	code := m.Run()
-
-// 	// dropErr := config.DropTestDatabase()
-// 	// if dropErr != nil {
-// 	// 	panic(dropErr)
-// 	// }
-
-// 	os.Exit(code)
-// }
-
-// func TestUserService_CreateUser(t *testing.T) {
-// 	db := setupTestDB(t)
-// 	repo := repositories.NewUserRepository(db)
-// 	svc := NewUserService(repo)
-
-// 	ctx := context.Background()
-// 	req := &dto.UserRequest{Username: "Arthur"}
-
-// 	user, err := svc.CreateUser(ctx, req)
-
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, user)
-// 	assert.Equal(t, "Arthur", user.Username)
-// }
-
-// func TestUserService_FindUserByID(t *testing.T) {
-// 	db := setupTestDB(t)
-// 	repo := repositories.NewUserRepository(db)
-// 	svc := NewUserService(repo)
-
-// 	ctx := context.Background()
-// 	db.Create(&model.User{Username: "TestUser"})
-
-// 	user, err := svc.FindUserByID(ctx, 1)
-
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, user)
-// 	assert.Equal(t, "TestUser", user.Username)
-// }
-
-// func TestUserService_FindAllUsers(t *testing.T) {
-// 	db := setupTestDB(t)
-// 	repo := repositories.NewUserRepository(db)
-// 	svc := NewUserService(repo)
-
-// 	ctx := context.Background()
-// 	db.Create(&model.User{Username: "User1"})
-// 	db.Create(&model.User{Username: "User2"})
-
-// 	users, err := svc.FindAllUsers(ctx)
-
-// 	assert.NoError(t, err)
-// 	assert.Len(t, users, 2)
-// 	assert.Equal(t, "User1", users[0].Username)
-// 	assert.Equal(t, "User2", users[1].Username)
-// }
-
 package services
 
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"Learn_Jenkins/domain/dto"
 	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/pkg/password"
+	"Learn_Jenkins/repositories"
+	"Learn_Jenkins/repositories/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
-type mockUserRepo struct {
-	createResp  *model.User
-	createErr   error
-	findResp    *model.User
-	findErr     error
-	findAllResp []*model.User
-	findAllErr  error
+var errDatabase = errors.New("db error")
+
+const testPassword = "correct-Horse-battery"
+
+// testHasher uses the cheapest bcrypt cost to keep tests fast.
+var testHasher = newHasher(password.Params{Algorithm: password.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+
+func newHasher(params password.Params) *password.Hasher {
+	hasher, err := password.NewHasher(params)
+	if err != nil {
+		panic(err)
+	}
+	return hasher
 }
 
-func (m *mockUserRepo) CreateUser(ctx context.Context, req *dto.UserRequest) (*model.User, error) {
-	return m.createResp, m.createErr
+func newTestService(t *testing.T) (UserService, *mocks.MockUserRepository) {
+	svc, repo, _ := newTestServiceWithTokens(t)
+	return svc, repo
 }
 
-func (m *mockUserRepo) FindUserByID(ctx context.Context, id uint) (*model.User, error) {
-	return m.findResp, m.findErr
+// newTestServiceWithTokens also returns the refresh token repository, which
+// only a password change touches.
+func newTestServiceWithTokens(t *testing.T) (UserService, *mocks.MockUserRepository, *mocks.MockRefreshTokenRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+	return NewUserService(repo, tokens, testHasher), repo, tokens
 }
 
-func (m *mockUserRepo) FindAllUsers(ctx context.Context) ([]*model.User, error) {
-	return m.findAllResp, m.findAllErr
+func mustHash(t *testing.T, hasher *password.Hasher, plain string) model.PasswordHash {
+	hash, err := hasher.Hash(plain)
+	require.NoError(t, err)
+	return model.PasswordHash(hash)
 }
 
-func TestUserService_CreateUser_WithMock_Success(t *testing.T) {
-	ctx := context.Background()
-	mock := &mockUserRepo{
-		createResp: &model.User{ID: 1, Username: "Arthur"},
+// hashOf matches a hash of plain made with the current parameters.
+func hashOf(plain string) func(model.PasswordHash) bool {
+	return func(hash model.PasswordHash) bool {
+		match, needsRehash, err := testHasher.Verify(plain, string(hash))
+		return err == nil && match && !needsRehash
 	}
-	svc := NewUserService(mock)
+}
 
-	resp, err := svc.CreateUser(ctx, &dto.UserRequest{Username: "Arthur"})
-	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.Equal(t, uint(1), resp.ID)
-	assert.Equal(t, "Arthur", resp.Username)
+// hashedRequest matches the copy of req the service hands to the repository:
+// same username, no plain password and a hash of the password.
+func hashedRequest(req *dto.UserRequest) gomock.Matcher {
+	return gomock.Cond(func(got *dto.UserRequest) bool {
+		return got != req && got.Username == req.Username && got.Password == "" && hashOf(req.Password)(got.PasswordHash)
+	})
 }
 
-func TestUserService_CreateUser_WithMock_RepoError(t *testing.T) {
-	ctx := context.Background()
-	mock := &mockUserRepo{
-		createErr: errors.New("db error"),
+func TestUserService_CreateUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoUser *model.User
+		repoErr  error
+		want     *dto.UserResponse
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			repoUser: &model.User{ID: 1, Username: "Arthur"},
+			want:     &dto.UserResponse{ID: 1, Username: "Arthur"},
+		},
+		{
+			name:    "duplicate username",
+			repoErr: repositories.ErrUsernameAlreadyExists,
+			wantErr: repositories.ErrUsernameAlreadyExists,
+		},
+		{
+			name:    "repository error",
+			repoErr: errDatabase,
+			wantErr: errDatabase,
+		},
 	}
-	svc := NewUserService(mock)
 
-	resp, err := svc.CreateUser(ctx, &dto.UserRequest{Username: "Arthur"})
-	assert.Error(t, err)
-	assert.Nil(t, resp)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(t)
+			req := &dto.UserRequest{Username: "Arthur", Password: testPassword}
+			repo.EXPECT().CreateUser(gomock.Any(), hashedRequest(req)).Return(tt.repoUser, tt.repoErr)
+
+			resp, err := svc.CreateUser(context.Background(), req)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
 }
 
-func TestUserService_FindUserByID_WithMock_Success(t *testing.T) {
-	ctx := context.Background()
-	mock := &mockUserRepo{
-		findResp: &model.User{ID: 2, Username: "TestUser"},
+func TestUserService_FindUserByID(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoUser *model.User
+		repoErr  error
+		want     *dto.UserResponse
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			repoUser: &model.User{ID: 2, Username: "TestUser"},
+			want:     &dto.UserResponse{ID: 2, Username: "TestUser"},
+		},
+		{
+			name:    "not found",
+			repoErr: repositories.ErrUserNotFound,
+			wantErr: repositories.ErrUserNotFound,
+		},
+		{
+			name:    "repository error",
+			repoErr: errDatabase,
+			wantErr: errDatabase,
+		},
 	}
-	svc := NewUserService(mock)
 
-	resp, err := svc.FindUserByID(ctx, 2)
-	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.Equal(t, uint(2), resp.ID)
-	assert.Equal(t, "TestUser", resp.Username)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(t)
+			repo.EXPECT().FindUserByID(gomock.Any(), uint(2)).Return(tt.repoUser, tt.repoErr)
+
+			resp, err := svc.FindUserByID(context.Background(), 2)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
 }
 
-func TestUserService_FindUserByID_WithMock_RepoError(t *testing.T) {
-	ctx := context.Background()
-	mock := &mockUserRepo{
-		findErr: errors.New("not found"),
+func TestUserService_FindAllUsers(t *testing.T) {
+	users := func(ids ...uint) []*model.User {
+		result := make([]*model.User, 0, len(ids))
+		for _, id := range ids {
+			result = append(result, &model.User{ID: id, Username: fmt.Sprintf("User%d", id)})
+		}
+		return result
+	}
+	after := &dto.UserCursor{ID: 7, Username: "User7"}
+	sort := []dto.SortField{{Field: "username", Desc: true}}
+
+	tests := []struct {
+		name      string
+		filter    dto.UserFilter
+		wantQuery dto.UserFilter
+		repoUsers []*model.User
+		repoTotal int64
+		repoErr   error
+		wantItems []uint
+		wantPage  dto.UserListResponse
+		wantErr   error
+	}{
+		{
+			name:      "defaults",
+			filter:    dto.UserFilter{},
+			wantQuery: dto.UserFilter{Limit: DefaultPageSize + 1},
+			repoUsers: users(1, 2),
+			repoTotal: 2,
+			wantItems: []uint{1, 2},
+			wantPage:  dto.UserListResponse{Total: 2, Limit: DefaultPageSize},
+		},
+		{
+			name:      "empty list",
+			filter:    dto.UserFilter{Limit: 10},
+			wantQuery: dto.UserFilter{Limit: 11},
+			repoUsers: []*model.User{},
+			wantItems: []uint{},
+			wantPage:  dto.UserListResponse{Limit: 10},
+		},
+		{
+			name:      "limit and offset are clamped",
+			filter:    dto.UserFilter{Limit: MaxPageSize + 1, Offset: -1},
+			wantQuery: dto.UserFilter{Limit: MaxPageSize + 1},
+			wantItems: []uint{},
+			wantPage:  dto.UserListResponse{Limit: MaxPageSize},
+		},
+		{
+			name:      "extra row sets has_more",
+			filter:    dto.UserFilter{Limit: 2, Offset: 4},
+			wantQuery: dto.UserFilter{Limit: 3, Offset: 4},
+			repoUsers: users(5, 6, 7),
+			repoTotal: 9,
+			wantItems: []uint{5, 6},
+			wantPage:  dto.UserListResponse{Total: 9, Limit: 2, Offset: 4, HasMore: true},
+		},
+		{
+			name:      "exactly one page",
+			filter:    dto.UserFilter{Limit: 2},
+			wantQuery: dto.UserFilter{Limit: 3},
+			repoUsers: users(1, 2),
+			repoTotal: 2,
+			wantItems: []uint{1, 2},
+			wantPage:  dto.UserListResponse{Total: 2, Limit: 2},
+		},
+		{
+			name:      "sort, prefix and cursor are passed through",
+			filter:    dto.UserFilter{Limit: 5, Sort: sort, UsernamePrefix: "Us", After: after},
+			wantQuery: dto.UserFilter{Limit: 6, Sort: sort, UsernamePrefix: "Us", After: after},
+			repoUsers: users(8),
+			repoTotal: 9,
+			wantItems: []uint{8},
+			wantPage:  dto.UserListResponse{Total: 9, Limit: 5},
+		},
+		{
+			name:      "invalid sort field",
+			filter:    dto.UserFilter{Limit: 5},
+			wantQuery: dto.UserFilter{Limit: 6},
+			repoErr:   repositories.ErrInvalidSortField,
+			wantErr:   repositories.ErrInvalidSortField,
+		},
+		{
+			name:      "repository error",
+			filter:    dto.UserFilter{Limit: 5},
+			wantQuery: dto.UserFilter{Limit: 6},
+			repoErr:   errDatabase,
+			wantErr:   errDatabase,
+		},
 	}
-	svc := NewUserService(mock)
 
-	resp, err := svc.FindUserByID(ctx, 2)
-	assert.Error(t, err)
-	assert.Nil(t, resp)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(t)
+			repo.EXPECT().FindAllUsers(gomock.Any(), &tt.wantQuery).Return(tt.repoUsers, tt.repoTotal, tt.repoErr)
+
+			resp, err := svc.FindAllUsers(context.Background(), &tt.filter)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Nil(t, resp)
+				return
+			}
+			assert.NotNil(t, resp.Items)
+			ids := []uint{}
+			for _, item := range resp.Items {
+				ids = append(ids, item.ID)
+			}
+			assert.Equal(t, tt.wantItems, ids)
+			assert.Equal(t, tt.wantPage.Total, resp.Total)
+			assert.Equal(t, tt.wantPage.Limit, resp.Limit)
+			assert.Equal(t, tt.wantPage.Offset, resp.Offset)
+			assert.Equal(t, tt.wantPage.HasMore, resp.HasMore)
+		})
+	}
 }
 
-func TestUserService_FindAllUsers_WithMock_Success(t *testing.T) {
-	ctx := context.Background()
-	mock := &mockUserRepo{
-		findAllResp: []*model.User{
-			{ID: 1, Username: "User1"},
-			{ID: 2, Username: "User2"},
+func TestUserService_UpdateUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoUser  *model.User
+		repoErr   error
+		revokeErr error
+		want      *dto.UserResponse
+		wantErr   error
+	}{
+		{
+			name:     "success",
+			repoUser: &model.User{ID: 1, Username: "Renamed"},
+			want:     &dto.UserResponse{ID: 1, Username: "Renamed"},
+		},
+		{
+			name:      "revoking sessions fails",
+			repoUser:  &model.User{ID: 1, Username: "Renamed"},
+			revokeErr: errDatabase,
+			wantErr:   errDatabase,
+		},
+		{
+			name:    "not found",
+			repoErr: repositories.ErrUserNotFound,
+			wantErr: repositories.ErrUserNotFound,
+		},
+		{
+			name:    "duplicate username",
+			repoErr: repositories.ErrUsernameAlreadyExists,
+			wantErr: repositories.ErrUsernameAlreadyExists,
 		},
 	}
-	svc := NewUserService(mock)
 
-	resp, err := svc.FindAllUsers(ctx)
-	assert.NoError(t, err)
-	assert.Len(t, resp, 2)
-	assert.Equal(t, "User1", resp[0].Username)
-	assert.Equal(t, "User2", resp[1].Username)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, tokens := newTestServiceWithTokens(t)
+			req := &dto.UserRequest{Username: "Renamed", Password: testPassword}
+			repo.EXPECT().UpdateUser(gomock.Any(), uint(1), hashedRequest(req)).Return(tt.repoUser, tt.repoErr)
+			if tt.repoErr == nil {
+				tokens.EXPECT().RevokeUserRefreshTokens(gomock.Any(), uint(1), gomock.Any()).Return(tt.revokeErr)
+			}
+
+			resp, err := svc.UpdateUser(context.Background(), 1, req)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
 }
 
-func TestUserService_FindAllUsers_WithMock_RepoError(t *testing.T) {
-	ctx := context.Background()
-	mock := &mockUserRepo{
-		findAllErr: errors.New("db failure"),
+func TestUserService_PatchUser(t *testing.T) {
+	username := "Patched"
+	tests := []struct {
+		name     string
+		req      *dto.UserPatchRequest
+		repoUser *model.User
+		repoErr  error
+		want     *dto.UserResponse
+		wantErr  error
+	}{
+		{
+			name:     "rename",
+			req:      &dto.UserPatchRequest{Username: &username},
+			repoUser: &model.User{ID: 1, Username: "Patched"},
+			want:     &dto.UserResponse{ID: 1, Username: "Patched"},
+		},
+		{
+			name:     "empty patch",
+			req:      &dto.UserPatchRequest{},
+			repoUser: &model.User{ID: 1, Username: "Arthur"},
+			want:     &dto.UserResponse{ID: 1, Username: "Arthur"},
+		},
+		{
+			name:    "duplicate username",
+			req:     &dto.UserPatchRequest{Username: &username},
+			repoErr: repositories.ErrUsernameAlreadyExists,
+			wantErr: repositories.ErrUsernameAlreadyExists,
+		},
+		{
+			name:    "not found",
+			req:     &dto.UserPatchRequest{},
+			repoErr: repositories.ErrUserNotFound,
+			wantErr: repositories.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(t)
+			repo.EXPECT().PatchUser(gomock.Any(), uint(1), tt.req).Return(tt.repoUser, tt.repoErr)
+
+			resp, err := svc.PatchUser(context.Background(), 1, tt.req)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+	}{
+		{name: "success"},
+		{name: "not found", repoErr: repositories.ErrUserNotFound},
+		{name: "repository error", repoErr: errDatabase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(t)
+			repo.EXPECT().DeleteUser(gomock.Any(), uint(1)).Return(tt.repoErr)
+
+			err := svc.DeleteUser(context.Background(), 1)
+
+			assert.Equal(t, tt.repoErr, err)
+		})
 	}
-	svc := NewUserService(mock)
+}
+
+func TestUserService_PassesContext(t *testing.T) {
+	svc, repo := newTestService(t)
+	ctx := context.WithValue(context.Background(), struct{}{}, "request")
+	repo.EXPECT().FindUserByID(ctx, uint(1)).Return(&model.User{ID: 1, Username: "Arthur"}, nil)
+
+	_, err := svc.FindUserByID(ctx, 1)
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_PatchUser_HashesPassword(t *testing.T) {
+	svc, repo, tokens := newTestServiceWithTokens(t)
+	plain := testPassword
+	req := &dto.UserPatchRequest{Password: &plain}
+	repo.EXPECT().PatchUser(gomock.Any(), uint(1), gomock.Cond(func(got *dto.UserPatchRequest) bool {
+		return got.Username == nil && got.Password == nil && got.PasswordHash != nil && hashOf(testPassword)(*got.PasswordHash)
+	})).Return(&model.User{ID: 1, Username: "Arthur"}, nil)
+	tokens.EXPECT().RevokeUserRefreshTokens(gomock.Any(), uint(1), gomock.Any()).Return(nil)
 
-	resp, err := svc.FindAllUsers(ctx)
-	assert.Error(t, err)
+	resp, err := svc.PatchUser(context.Background(), 1, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.UserResponse{ID: 1, Username: "Arthur"}, resp)
+	assert.Equal(t, testPassword, *req.Password, "the caller's request is left untouched")
+	assert.Nil(t, req.PasswordHash)
+}
+
+func TestUserService_PatchUser_PasswordRevokeFails(t *testing.T) {
+	svc, repo, tokens := newTestServiceWithTokens(t)
+	plain := testPassword
+	repo.EXPECT().PatchUser(gomock.Any(), uint(1), gomock.Any()).Return(&model.User{ID: 1, Username: "Arthur"}, nil)
+	tokens.EXPECT().RevokeUserRefreshTokens(gomock.Any(), uint(1), gomock.Any()).Return(errDatabase)
+
+	resp, err := svc.PatchUser(context.Background(), 1, &dto.UserPatchRequest{Password: &plain})
+
+	assert.Equal(t, errDatabase, err)
 	assert.Nil(t, resp)
 }
+
+func TestUserService_Authenticate(t *testing.T) {
+	current := mustHash(t, testHasher, testPassword)
+	outdated := mustHash(t, newHasher(password.Params{
+		Algorithm: password.AlgorithmArgon2id, Argon2Memory: 8, Argon2Iterations: 1, Argon2Parallelism: 1,
+	}), testPassword)
+	user := func(hash model.PasswordHash) *model.User {
+		return &model.User{ID: 1, Username: "Arthur", PasswordHash: hash}
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		repoUser  *model.User
+		repoErr   error
+		rehash    bool
+		rehashErr error
+		want      *dto.UserResponse
+		wantErr   error
+	}{
+		{
+			name:     "current hash",
+			password: testPassword,
+			repoUser: user(current),
+			want:     &dto.UserResponse{ID: 1, Username: "Arthur"},
+		},
+		{
+			name:     "outdated hash is upgraded",
+			password: testPassword,
+			repoUser: user(outdated),
+			rehash:   true,
+			want:     &dto.UserResponse{ID: 1, Username: "Arthur"},
+		},
+		{
+			name:      "failed upgrade still logs in",
+			password:  testPassword,
+			repoUser:  user(outdated),
+			rehash:    true,
+			rehashErr: errDatabase,
+			want:      &dto.UserResponse{ID: 1, Username: "Arthur"},
+		},
+		{
+			name:     "wrong password",
+			password: "wrong-Horse-battery",
+			repoUser: user(outdated),
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "account without password",
+			password: testPassword,
+			repoUser: user(""),
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown username",
+			password: testPassword,
+			repoErr:  repositories.ErrUserNotFound,
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "repository error",
+			password: testPassword,
+			repoErr:  errDatabase,
+			wantErr:  errDatabase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newTestService(t)
+			repo.EXPECT().FindUserByUsername(gomock.Any(), "Arthur").Return(tt.repoUser, tt.repoErr)
+			if tt.rehash {
+				repo.EXPECT().PatchUser(gomock.Any(), uint(1), gomock.Cond(func(got *dto.UserPatchRequest) bool {
+					return got.Username == nil && got.PasswordHash != nil && hashOf(testPassword)(*got.PasswordHash)
+				})).Return(tt.repoUser, tt.rehashErr)
+			}
+
+			resp, err := svc.Authenticate(context.Background(), "Arthur", tt.password)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"Learn_Jenkins/domain/apperror"
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/pkg/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// userServiceTracing wraps a UserService with one span per method. Client
+// errors such as not found or conflict are tagged with their code; only
+// internal errors mark the span as failed.
+type userServiceTracing struct {
+	next UserService
+}
+
+func NewTracingUserService(next UserService) UserService {
+	return &userServiceTracing{next: next}
+}
+
+func (s *userServiceTracing) CreateUser(ctx context.Context, req *dto.UserRequest) (*dto.UserResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.CreateUser")
+	resp, err := s.next.CreateUser(ctx, req)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (s *userServiceTracing) FindUserByID(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.FindUserByID",
+		trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	resp, err := s.next.FindUserByID(ctx, id)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (s *userServiceTracing) FindAllUsers(ctx context.Context, filter *dto.UserFilter) (*dto.UserListResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.FindAllUsers")
+	resp, err := s.next.FindAllUsers(ctx, filter)
+	if err == nil {
+		span.SetAttributes(attribute.Int("users.count", len(resp.Items)), attribute.Int64("users.total", resp.Total))
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+func (s *userServiceTracing) UpdateUser(ctx context.Context, id uint, req *dto.UserRequest) (*dto.UserResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.UpdateUser",
+		trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	resp, err := s.next.UpdateUser(ctx, id, req)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (s *userServiceTracing) PatchUser(ctx context.Context, id uint, req *dto.UserPatchRequest) (*dto.UserResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.PatchUser",
+		trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	resp, err := s.next.PatchUser(ctx, id, req)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (s *userServiceTracing) DeleteUser(ctx context.Context, id uint) error {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.DeleteUser",
+		trace.WithAttributes(attribute.Int64("user.id", int64(id))))
+	err := s.next.DeleteUser(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *userServiceTracing) Authenticate(ctx context.Context, username, password string) (*dto.UserResponse, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "UserService.Authenticate")
+	resp, err := s.next.Authenticate(ctx, username, password)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("user.id", int64(resp.ID)))
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+func endSpan(span trace.Span, err error) {
+	if err == nil {
+		span.End()
+		return
+	}
+	appErr := apperror.From(err)
+	span.SetAttributes(attribute.String("error.code", appErr.Code))
+	if appErr.Kind != apperror.KindInternal {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"Learn_Jenkins/domain/dto"
+	"Learn_Jenkins/domain/model"
+	"Learn_Jenkins/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracingUserService(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	next, repo := newTestService(t)
+	svc := NewTracingUserService(next)
+
+	var repoSpan trace.SpanContext
+	repo.EXPECT().FindUserByID(gomock.Any(), uint(1)).DoAndReturn(func(ctx context.Context, id uint) (*model.User, error) {
+		repoSpan = trace.SpanContextFromContext(ctx)
+		return &model.User{ID: 1, Username: "Arthur"}, nil
+	})
+	repo.EXPECT().DeleteUser(gomock.Any(), uint(2)).Return(repositories.ErrUserNotFound)
+	repo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, errDatabase)
+
+	resp, err := svc.FindUserByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.UserResponse{ID: 1, Username: "Arthur"}, resp)
+	assert.ErrorIs(t, svc.DeleteUser(context.Background(), 2), repositories.ErrUserNotFound)
+	_, err = svc.CreateUser(context.Background(), &dto.UserRequest{Username: "Arthur"})
+	assert.Equal(t, errDatabase, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	found := spans[0]
+	assert.Equal(t, "UserService.FindUserByID", found.Name())
+	assert.Equal(t, found.SpanContext(), repoSpan, "the repository receives the service span")
+	assert.Contains(t, found.Attributes(), attribute.Int64("user.id", 1))
+	assert.Equal(t, codes.Unset, found.Status().Code)
+
+	notFound := spans[1]
+	assert.Equal(t, "UserService.DeleteUser", notFound.Name())
+	assert.Contains(t, notFound.Attributes(), attribute.String("error.code", "user_not_found"))
+	assert.Equal(t, codes.Unset, notFound.Status().Code)
+
+	failed := spans[2]
+	assert.Equal(t, "UserService.CreateUser", failed.Name())
+	assert.Contains(t, failed.Attributes(), attribute.String("error.code", "internal_error"))
+	assert.Equal(t, codes.Error, failed.Status().Code)
+}